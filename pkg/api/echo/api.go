@@ -1,11 +1,14 @@
 package echo
 
 import (
+	"context"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echolog "github.com/labstack/gommon/log"
+	"github.com/mangalores/go-api-skeleton/pkg/config"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
@@ -14,14 +17,22 @@ type RouteHandler interface {
 }
 
 type App struct {
-	echo *echo.Echo
-	Addr string
+	echo     *echo.Echo
+	Addr     string
+	config   config.App
+	problems *ProblemHandler
 }
 
 func NewApp(echo *echo.Echo, addr string) *App {
+	return NewAppFromConfig(echo, config.App{Addr: addr, LogLevel: "debug", Swagger: true})
+}
+
+func NewAppFromConfig(echo *echo.Echo, c config.App) *App {
 	a := App{
 		echo,
-		addr,
+		c.Addr,
+		c,
+		NewProblemHandler(c.ProblemBaseURI),
 	}
 
 	a.configureEcho()
@@ -29,6 +40,11 @@ func NewApp(echo *echo.Echo, addr string) *App {
 	return &a
 }
 
+// Problems error handler of the app, to register the problems of service specific errors
+func (a *App) Problems() *ProblemHandler {
+	return a.problems
+}
+
 func (a *App) BindRoutes(h RouteHandler) {
 	h.Bind(a.echo)
 }
@@ -36,14 +52,48 @@ func (a *App) BindRoutes(h RouteHandler) {
 func (a *App) configureEcho() {
 	e := a.echo
 
-	e.Logger.SetLevel(echolog.DEBUG)
+	e.Logger.SetLevel(parseLogLevel(a.config.LogLevel))
+	e.HTTPErrorHandler = a.problems.Handle
 	e.Pre(middleware.RemoveTrailingSlash())
+	e.Use(middleware.RequestID())
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	e.Use(corsMiddleware(a.config.CORS))
 
-	e.GET("/", Redirect("/swagger/index.html"))
-	e.GET("/swagger*", echoSwagger.WrapHandler)
+	if a.config.Swagger {
+		e.GET("/", Redirect("/swagger/index.html"))
+		e.GET("/swagger*", echoSwagger.WrapHandler)
+	}
+}
+
+func corsMiddleware(c config.CORS) echo.MiddlewareFunc {
+	cors := middleware.DefaultCORSConfig
+	if len(c.AllowOrigins) > 0 {
+		cors.AllowOrigins = c.AllowOrigins
+	}
+	if len(c.AllowMethods) > 0 {
+		cors.AllowMethods = c.AllowMethods
+	}
+	cors.AllowHeaders = c.AllowHeaders
+	cors.AllowCredentials = c.AllowCredentials
+	cors.MaxAge = c.MaxAge
+
+	return middleware.CORSWithConfig(cors)
+}
+
+func parseLogLevel(logLevel string) echolog.Lvl {
+	switch strings.ToLower(logLevel) {
+	case "debug", "trace":
+		return echolog.DEBUG
+	case "info":
+		return echolog.INFO
+	case "warn", "warning":
+		return echolog.WARN
+	case "error":
+		return echolog.ERROR
+	default:
+		return echolog.OFF
+	}
 }
 
 // Serve start listening at addr
@@ -54,6 +104,16 @@ func (a *App) Serve() error {
 	return a.echo.Start(a.Addr)
 }
 
+// Shutdown stops the server gracefully, waiting for active requests until ctx is done
+func (a *App) Shutdown(ctx context.Context) error {
+	return a.echo.Shutdown(ctx)
+}
+
+// Routes bound to the app
+func (a *App) Routes() []*echo.Route {
+	return a.echo.Routes()
+}
+
 func Redirect(url string) func(ctx echo.Context) error {
 	return func(ctx echo.Context) error { return ctx.Redirect(http.StatusPermanentRedirect, url) }
 }
@@ -0,0 +1,103 @@
+package echo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mangalores/go-api-skeleton/pkg/api/query_builder"
+	rh "github.com/mangalores/go-api-skeleton/pkg/api/response_handler"
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+)
+
+const exportFlushSize = 1000
+
+// AllowExport exports stream all matching rows, _limit caps the exported rows without the max limit of collections
+// exports of resources not allowing it are limited to the requested page
+func (r *Resource[T]) AllowExport(flag bool) {
+	r.exportAll = flag
+}
+
+// export streams the rows of the collection query without counting, the writer is flushed every exportFlushSize rows
+// errors after the first row are logged by the error handler only as the response is committed already
+func (r *Resource[T]) export(ctx echo.Context, exporter rh.Exporter) error {
+	params, err := queryParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	limit := 0
+	if r.exportAll {
+		if value := params.Get(query_builder.LimitField); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				err = query_builder.NewInvalidParamValueErr(query_builder.LimitField, true)
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+			limit = n
+		}
+		params.Del(query_builder.LimitField)
+	}
+
+	q, err := r.collection.Build(params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	sq := q.(db.StreamedQueryObject)
+	sq.SetStream(true)
+	if r.exportAll {
+		sq.Slice().Limit = limit
+	}
+
+	if err = r.handle(ctx, List, sq); err != nil {
+		if it := sq.Iterator(); it != nil {
+			it.Close()
+		}
+		return err
+	}
+	it := sq.Iterator()
+	if it == nil {
+		// repositories not supporting streams set the result
+		it = db.NewSliceIterator(sq.Result())
+	}
+	defer it.Close()
+
+	// the first row is mapped before the response is committed, failing mappings respond an error instead
+	var first json.RawMessage
+	if it.Next() {
+		if first, err = r.responses.MapRow(sq, it.Value()); err != nil {
+			return err
+		}
+	}
+	if err = it.Err(); err != nil {
+		return err
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentType, exporter.MediaType())
+	ctx.Response().WriteHeader(http.StatusOK)
+	writer := exporter.NewWriter(ctx.Response(), r.responses.ExportColumns(sq, first))
+	if first == nil {
+		return writer.Flush()
+	}
+	if err = writer.Write(first); err != nil {
+		return err
+	}
+
+	for rows := 2; it.Next(); rows++ {
+		if err = r.responses.ExportRow(writer, sq, it.Value()); err != nil {
+			return err
+		}
+		if rows%exportFlushSize == 0 {
+			if err = writer.Flush(); err != nil {
+				return err
+			}
+			ctx.Response().Flush()
+		}
+	}
+	if err = it.Err(); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,149 @@
+package echo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mangalores/go-api-skeleton/pkg/api/query_builder"
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+const defaultProblemBaseURI = "/problems/"
+
+// Problem details of an error response, RFC 7807
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Param    string `json:"param,omitempty"`
+	TraceID  string `json:"traceId,omitempty"`
+}
+
+// ProblemFunc maps errors to problems, ok is false for errors not handled
+type ProblemFunc func(err error) (problem Problem, ok bool)
+
+// ProblemHandler echo HTTPErrorHandler responding application/problem+json
+// the type of problems is the base uri followed by the slug of the error, e.g. /problems/invalid-parameter
+type ProblemHandler struct {
+	baseURI  string
+	problems []ProblemFunc
+}
+
+func NewProblemHandler(baseURI string) *ProblemHandler {
+	if baseURI == "" {
+		baseURI = defaultProblemBaseURI
+	}
+
+	h := &ProblemHandler{baseURI: baseURI}
+	h.registerDefaultProblems()
+
+	return h
+}
+
+func (h *ProblemHandler) registerDefaultProblems() {
+	RegisterProblem[query_builder.InvalidParamValueErr](h, http.StatusBadRequest, "invalid-parameter", "Invalid parameter value")
+	RegisterProblem[query_builder.MaxLimitExceededErr](h, http.StatusBadRequest, "limit-exceeded", "Limit exceeded")
+	RegisterProblem[query_builder.InvalidFilterErr](h, http.StatusBadRequest, "invalid-filter", "Invalid filter")
+	RegisterProblem[query_builder.InvalidMultipleValuesErr](h, http.StatusBadRequest, "invalid-filter", "Invalid filter")
+	RegisterProblem[query_builder.InvalidFilterExpressionErr](h, http.StatusBadRequest, "invalid-filter-expression", "Invalid filter expression")
+	RegisterProblem[query_builder.InvalidEmbedErr](h, http.StatusBadRequest, "invalid-embed", "Invalid embed")
+	RegisterProblem[db.EntityNotFoundErr](h, http.StatusNotFound, "not-found", "Not found")
+	RegisterProblem[db.MissingConditionsErr](h, http.StatusBadRequest, "missing-conditions", "Missing conditions")
+	RegisterProblem[db.EntityIDConflictErr](h, http.StatusConflict, "id-conflict", "Id conflict")
+	RegisterProblem[db.InvalidCursorErr](h, http.StatusBadRequest, "invalid-cursor", "Invalid cursor")
+	RegisterProblem[db.NullableKeysetFieldErr](h, http.StatusBadRequest, "invalid-sort", "Invalid sort")
+	RegisterProblem[db.InvalidConditionValueErr](h, http.StatusBadRequest, "invalid-filter", "Invalid filter")
+	RegisterProblem[db.UnknownRelationErr](h, http.StatusBadRequest, "invalid-filter", "Invalid filter")
+	RegisterProblem[db.UnsupportedStreamPreloadErr](h, http.StatusBadRequest, "invalid-embed", "Invalid embed")
+	h.Register(func(err error) (Problem, bool) {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return Problem{}, false
+		}
+		return Problem{Type: h.baseURI + "timeout", Title: "Timeout", Status: http.StatusGatewayTimeout}, true
+	})
+}
+
+// Register adds a mapping, later registrations take precedence
+func (h *ProblemHandler) Register(fn ProblemFunc) {
+	h.problems = append(h.problems, fn)
+}
+
+// RegisterProblem maps errors of type E, the error message is the detail
+// the param is set for errors naming the offending parameter by a Name method
+// RegisterProblem[MyErr](h, http.StatusConflict, "conflict", "Conflict") => {"type": "/problems/conflict", ...}
+func RegisterProblem[E error](h *ProblemHandler, status int, slug string, title string) {
+	h.Register(func(err error) (Problem, bool) {
+		var target E
+		if !errors.As(err, &target) {
+			return Problem{}, false
+		}
+
+		problem := Problem{
+			Type:   h.baseURI + slug,
+			Title:  title,
+			Status: status,
+			Detail: target.Error(),
+		}
+		if named, ok := any(target).(interface{ Name() string }); ok {
+			problem.Param = named.Name()
+		}
+
+		return problem, true
+	})
+}
+
+// Problem of err, errors not registered are internal server errors without details unless they are echo http errors
+func (h *ProblemHandler) Problem(err error) Problem {
+	for i := len(h.problems) - 1; i >= 0; i-- {
+		if problem, ok := h.problems[i](err); ok {
+			return problem
+		}
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		problem := Problem{Type: "about:blank", Title: http.StatusText(httpErr.Code), Status: httpErr.Code}
+		if msg, ok := httpErr.Message.(string); ok && msg != problem.Title {
+			problem.Detail = msg
+		}
+		return problem
+	}
+
+	return Problem{Type: "about:blank", Title: http.StatusText(http.StatusInternalServerError), Status: http.StatusInternalServerError}
+}
+
+// Handle implements echo.HTTPErrorHandler, the trace id is the request id set by the RequestID middleware
+// errors of committed responses, e.g. of interrupted exports, are logged only
+func (h *ProblemHandler) Handle(err error, ctx echo.Context) {
+	if ctx.Response().Committed {
+		ctx.Logger().Error(err)
+		return
+	}
+
+	problem := h.Problem(err)
+	if problem.Status >= http.StatusInternalServerError {
+		ctx.Logger().Error(err)
+	}
+	problem.Instance = ctx.Request().URL.Path
+	problem.TraceID = ctx.Response().Header().Get(echo.HeaderXRequestID)
+	if problem.TraceID == "" {
+		problem.TraceID = ctx.Request().Header.Get(echo.HeaderXRequestID)
+	}
+
+	if ctx.Request().Method == http.MethodHead {
+		err = ctx.NoContent(problem.Status)
+	} else {
+		ctx.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+		ctx.Response().WriteHeader(problem.Status)
+		err = ctx.Echo().JSONSerializer.Serialize(ctx, problem, "")
+	}
+	if err != nil {
+		ctx.Logger().Error(err)
+	}
+}
@@ -0,0 +1,127 @@
+package echo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/mangalores/go-api-skeleton/pkg/api/query_builder"
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type conflictErr struct{}
+
+func (e conflictErr) Error() string {
+	return "already exists"
+}
+
+func TestProblemHandler_Problem(t *testing.T) {
+	h := NewProblemHandler("https://example.com/problems/")
+	RegisterProblem[conflictErr](h, http.StatusConflict, "conflict", "Conflict")
+
+	tests := []struct {
+		err      error
+		expected Problem
+	}{
+		{
+			query_builder.NewInvalidParamValueErr("_limit", true),
+			Problem{Type: "https://example.com/problems/invalid-parameter", Title: "Invalid parameter value", Status: 400, Detail: "param _limit has invalid value must be numeric", Param: "_limit"},
+		},
+		{
+			echo.NewHTTPError(http.StatusNotFound, "not here").SetInternal(db.NewEntityNotFoundErr("Thing", 1)),
+			Problem{Type: "https://example.com/problems/not-found", Title: "Not found", Status: 404, Detail: "could not find Thing with id 1"},
+		},
+		{
+			db.NewEntityIDConflictErr("Thing", "1", 2),
+			Problem{Type: "https://example.com/problems/id-conflict", Title: "Id conflict", Status: 409, Detail: "Thing with id 2 cannot be written to id 1"},
+		},
+		{
+			// a resource without repository is a misconfiguration of the server
+			db.NewRepositoryNotFound(&[]string{}),
+			Problem{Type: "about:blank", Title: "Internal Server Error", Status: 500},
+		},
+		{
+			db.NewInvalidCursorErr("unknown field Foo"),
+			Problem{Type: "https://example.com/problems/invalid-cursor", Title: "Invalid cursor", Status: 400, Detail: "invalid cursor: unknown field Foo"},
+		},
+		{
+			db.NewNullableKeysetFieldErr("Rank"),
+			Problem{Type: "https://example.com/problems/invalid-sort", Title: "Invalid sort", Status: 400, Detail: "cannot paginate by cursor sorted by nullable field Rank"},
+		},
+		{
+			db.NewInvalidConditionValueErr("BETWEEN", 1),
+			Problem{Type: "https://example.com/problems/invalid-filter", Title: "Invalid filter", Status: 400, Detail: "invalid value 1 for operator BETWEEN"},
+		},
+		{
+			db.NewUnknownRelationErr("Owner"),
+			Problem{Type: "https://example.com/problems/invalid-filter", Title: "Invalid filter", Status: 400, Detail: "unknown relation Owner"},
+		},
+		{
+			db.NewUnsupportedStreamPreloadErr(),
+			Problem{Type: "https://example.com/problems/invalid-embed", Title: "Invalid embed", Status: 400, Detail: "preloads are not supported by streamed queries"},
+		},
+		{
+			fmt.Errorf("wrapped: %w", conflictErr{}),
+			Problem{Type: "https://example.com/problems/conflict", Title: "Conflict", Status: 409, Detail: "already exists"},
+		},
+		{
+			fmt.Errorf("query: %w", context.DeadlineExceeded),
+			Problem{Type: "https://example.com/problems/timeout", Title: "Timeout", Status: 504},
+		},
+		{
+			echo.ErrMethodNotAllowed,
+			Problem{Type: "about:blank", Title: "Method Not Allowed", Status: 405},
+		},
+		{
+			errors.New("connection refused"),
+			Problem{Type: "about:blank", Title: "Internal Server Error", Status: 500},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, h.Problem(test.err))
+	}
+}
+
+func TestProblemHandler_Handle(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = NewProblemHandler("").Handle
+	e.Use(middleware.RequestID())
+	e.GET("/things", func(ctx echo.Context) error {
+		return query_builder.NewInvalidEmbedErr("owner")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/things", nil)
+	req.Header.Set(echo.HeaderXRequestID, "trace-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.JSONEq(t, `{"type":"/problems/invalid-embed","title":"Invalid embed","status":400,"detail":"invalid embed name requested: owner","instance":"/things","param":"_embed","traceId":"trace-1"}`, rec.Body.String())
+}
+
+func TestProblemHandler_HandleCommitted(t *testing.T) {
+	e := echo.New()
+	out := bytes.Buffer{}
+	e.Logger.SetOutput(&out)
+	e.HTTPErrorHandler = NewProblemHandler("").Handle
+	e.GET("/things", func(ctx echo.Context) error {
+		ctx.Response().WriteHeader(http.StatusOK)
+		return errors.New("export interrupted")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/things", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+	assert.Contains(t, out.String(), "export interrupted")
+}
@@ -0,0 +1,342 @@
+package echo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mangalores/go-api-skeleton/pkg/api/query_builder"
+	rh "github.com/mangalores/go-api-skeleton/pkg/api/response_handler"
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/mangalores/go-api-skeleton/pkg/utils"
+)
+
+type Action string
+
+const (
+	List   Action = "list"
+	Get    Action = "get"
+	Create Action = "create"
+	Update Action = "update"
+	Patch  Action = "patch"
+	Delete Action = "delete"
+)
+
+var allActions = []Action{List, Get, Create, Update, Patch, Delete}
+
+// Hook runs before the query of an action is handled or after, returning an error aborts the request
+type Hook func(ctx echo.Context, query db.QueryObject) error
+
+// Resource binds the CRUD routes of model T
+// GET path, GET path/:id, POST path, PUT path/:id, PATCH path/:id, DELETE path/:id
+// mappings for T and []T have to be registered at the response handler
+type Resource[T any] struct {
+	path       string
+	manager    *db.QueryManager
+	responses  *rh.ResponseHandler
+	collection *query_builder.QueryBuilder
+	entity     *query_builder.QueryBuilder
+	actions    []Action
+	timeout    time.Duration
+	exportAll  bool
+	before     map[Action][]Hook
+	after      map[Action][]Hook
+}
+
+func NewResource[T any](path string, manager *db.QueryManager, responses *rh.ResponseHandler) *Resource[T] {
+	collection := query_builder.NewQueryBuilder(&[]T{})
+	collection.SetSlice(true)
+
+	return &Resource[T]{
+		path:       path,
+		manager:    manager,
+		responses:  responses,
+		collection: collection,
+		entity:     query_builder.NewQueryBuilder(new(T)),
+		actions:    allActions,
+		before:     make(map[Action][]Hook),
+		after:      make(map[Action][]Hook),
+	}
+}
+
+// Collection query builder of the list action, e.g. to set filter policies or default sorting
+func (r *Resource[T]) Collection() *query_builder.QueryBuilder {
+	return r.collection
+}
+
+// Entity query builder of the get action, e.g. to allow embeds
+func (r *Resource[T]) Entity() *query_builder.QueryBuilder {
+	return r.entity
+}
+
+// SetTimeout timeout of the queries of all actions, queries are cancelled with the request as well
+func (r *Resource[T]) SetTimeout(timeout time.Duration) {
+	r.timeout = timeout
+	r.collection.SetTimeout(timeout)
+	r.entity.SetTimeout(timeout)
+}
+
+// SetActions restricts the bound routes to actions
+func (r *Resource[T]) SetActions(actions ...Action) {
+	r.actions = actions
+}
+
+func (r *Resource[T]) Before(action Action, hooks ...Hook) {
+	r.before[action] = append(r.before[action], hooks...)
+}
+
+// After hooks of List run for exports as well, before any row is read: the result is nil then
+// and the rows are read from the iterator of the db.StreamedQueryObject afterwards
+func (r *Resource[T]) After(action Action, hooks ...Hook) {
+	r.after[action] = append(r.after[action], hooks...)
+}
+
+func (r *Resource[T]) Bind(e *echo.Echo) {
+	item := r.path + "/:id"
+
+	for _, action := range r.actions {
+		switch action {
+		case List:
+			e.GET(r.path, r.List)
+		case Get:
+			e.GET(item, r.Get)
+		case Create:
+			e.POST(r.path, r.Create)
+		case Update:
+			e.PUT(item, r.Update)
+		case Patch:
+			e.PATCH(item, r.Patch)
+		case Delete:
+			e.DELETE(item, r.Delete)
+		}
+	}
+}
+
+// List responds exports for requests accepting an export media type, e.g. text/csv
+func (r *Resource[T]) List(ctx echo.Context) error {
+	if exporter, ok := r.responses.NegotiateExport(ctx.Request().Header.Get(echo.HeaderAccept)); ok {
+		return r.export(ctx, exporter)
+	}
+
+	params, err := queryParams(ctx)
+	if err != nil {
+		return err
+	}
+	q, err := r.collection.Build(params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	return r.respond(ctx, List, q, http.StatusOK)
+}
+
+func (r *Resource[T]) Get(ctx echo.Context) error {
+	params, err := queryParams(ctx)
+	if err != nil {
+		return err
+	}
+	q, err := r.entity.Build(params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	eq := db.NewEntityQuery(q.Model(), ctx.Param("id"))
+	eq.SetTimeout(q.Timeout())
+	preloads := q.Preloads()
+	eq.SetPreloads(&preloads)
+	eq.SetFields(q.Fields())
+	if fq, ok := q.(db.FilteredQueryObject); ok {
+		eq.SetFilters(fq.Filters())
+		eq.SetFilterGroups(fq.FilterGroups())
+	}
+
+	return r.respond(ctx, Get, eq, http.StatusOK)
+}
+
+func (r *Resource[T]) Create(ctx echo.Context) error {
+	entity := new(T)
+	if err := ctx.Bind(entity); err != nil {
+		return err
+	}
+	if err := resetProtectedFields(entity); err != nil {
+		return err
+	}
+
+	q := db.NewCreateQuery(entity)
+	q.SetOmitted(unwritableFields(reflect.TypeOf(entity).Elem()))
+	q.SetTimeout(r.timeout)
+
+	return r.respond(ctx, Create, q, http.StatusCreated)
+}
+
+func (r *Resource[T]) Update(ctx echo.Context) error {
+	entity := new(T)
+	if err := ctx.Bind(entity); err != nil {
+		return err
+	}
+	if err := resetProtectedFields(entity); err != nil {
+		return err
+	}
+
+	q := db.NewUpdateQuery(entity, ctx.Param("id"))
+	q.SetOmitted(unwritableFields(reflect.TypeOf(entity).Elem()))
+	q.SetTimeout(r.timeout)
+
+	return r.respond(ctx, Update, q, http.StatusOK)
+}
+
+func (r *Resource[T]) Patch(ctx echo.Context) error {
+	// binding path params into a map would fail, the body is bound only
+	body := make(map[string]json.RawMessage)
+	if err := (&echo.DefaultBinder{}).BindBody(ctx, &body); err != nil {
+		return err
+	}
+
+	values, err := patchValues(reflect.TypeOf(new(T)).Elem(), body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	q := db.NewPatchQuery(new(T), ctx.Param("id"), values)
+	q.SetTimeout(r.timeout)
+
+	return r.respond(ctx, Patch, q, http.StatusOK)
+}
+
+func (r *Resource[T]) Delete(ctx echo.Context) error {
+	q := db.NewDeleteQuery(new(T), ctx.Param("id"))
+	q.SetTimeout(r.timeout)
+	if err := r.handle(ctx, Delete, q); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// respond negotiates the representation before the query runs, writes are not applied for requests not acceptable
+func (r *Resource[T]) respond(ctx echo.Context, action Action, q db.QueryObject, status int) error {
+	representation, err := r.responses.Negotiate(ctx.Request().Header.Get(echo.HeaderAccept))
+	if err != nil {
+		return err
+	}
+
+	if err = r.handle(ctx, action, q); err != nil {
+		return err
+	}
+
+	res, err := r.responses.Map(ctx.Request().RequestURI, q)
+	if err != nil {
+		return err
+	}
+
+	return representation.Render(ctx, status, res)
+}
+
+// handle runs the query with the hooks of action, the query is cancelled if the request is cancelled
+// query errors are returned as is, their status is resolved by the problems registered at the ProblemHandler
+func (r *Resource[T]) handle(ctx echo.Context, action Action, q db.QueryObject) error {
+	q.SetContext(ctx.Request().Context())
+
+	if err := runHooks(ctx, r.before[action], q); err != nil {
+		return err
+	}
+
+	r.manager.Handle(q)
+	if err := q.Error(); err != nil {
+		return err
+	}
+
+	return runHooks(ctx, r.after[action], q)
+}
+
+func runHooks(ctx echo.Context, hooks []Hook, q db.QueryObject) error {
+	for _, hook := range hooks {
+		if err := hook(ctx, q); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchValues decodes the json values of the patch body into the types of the fields with matching json names,
+// protected fields like the primary key and fields ignored by api:"-" are refused
+// {"title": "foo"} => map[Title:foo]
+func patchValues(t reflect.Type, body map[string]json.RawMessage) (map[string]interface{}, error) {
+	protected, err := db.ProtectedFields(reflect.New(t).Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for _, field := range reflect.VisibleFields(t) {
+		if name, ok := utils.JSONName(field); ok && field.IsExported() {
+			fields[name] = field
+		}
+	}
+
+	values := make(map[string]interface{}, len(body))
+	for name, raw := range body {
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %s", name)
+		}
+		if utils.Contains(protected, field.Name) || query_builder.IgnoredField(field) {
+			return nil, fmt.Errorf("field %s is not writable", name)
+		}
+
+		value := reflect.New(field.Type)
+		if err := json.Unmarshal(raw, value.Interface()); err != nil {
+			return nil, fmt.Errorf("invalid value of field %s", name)
+		}
+		values[field.Name] = value.Elem().Interface()
+	}
+
+	return values, nil
+}
+
+// queryParams parses the raw query keeping ';' of filter groups and RSQL expressions
+func queryParams(ctx echo.Context) (url.Values, error) {
+	params, err := query_builder.ParseQuery(ctx.Request().URL.RawQuery)
+	if err != nil {
+		return params, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	return params, nil
+}
+
+// unwritableFields names of fields clients may not write, fields ignored by api:"-" or json:"-"
+func unwritableFields(t reflect.Type) []string {
+	fields := make([]string, 0)
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		if query_builder.IgnoredField(field) || field.Tag.Get("json") == "-" {
+			fields = append(fields, field.Name)
+		}
+	}
+
+	return fields
+}
+
+// resetProtectedFields zeroes protected fields bound from the body, ids are taken from the path or generated
+func resetProtectedFields(entity interface{}) error {
+	protected, err := db.ProtectedFields(entity)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	for _, name := range protected {
+		if field := v.FieldByName(name); field.CanSet() {
+			field.SetZero()
+		}
+	}
+
+	return nil
+}
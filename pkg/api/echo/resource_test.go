@@ -0,0 +1,312 @@
+package echo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	rh "github.com/mangalores/go-api-skeleton/pkg/api/response_handler"
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	mock_db "github.com/mangalores/go-api-skeleton/pkg/mocks/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockThing struct {
+	ID    int    `json:"id" api:"filter;sort"`
+	Title string `json:"title" api:"filter;sort"`
+	Views int    `json:"views" api:"filter;sort"`
+}
+
+func identity(e interface{}) (interface{}, error) {
+	return e, nil
+}
+
+func newTestResource(t *testing.T) (*echo.Echo, *mock_db.MockRepository) {
+	repo := mock_db.NewMockRepository(gomock.NewController(t))
+	repo.EXPECT().Supports(gomock.Any()).Return(true).AnyTimes()
+
+	manager := db.NewQueryManager(nil)
+	manager.Register(repo)
+
+	responses := rh.NewResponseHandler()
+	responses.Register(MockThing{}, identity)
+	responses.Register([]MockThing{}, identity)
+
+	e := echo.New()
+	e.HTTPErrorHandler = NewProblemHandler("").Handle
+	NewResource[MockThing]("/things", manager, responses).Bind(e)
+
+	return e, repo
+}
+
+func serve(e *echo.Echo, method string, target string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestResource_List(t *testing.T) {
+	e, repo := newTestResource(t)
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		assert.IsType(t, &db.CollectionQuery{}, q)
+		q.SetResult(&[]MockThing{{ID: 1, Title: "foo"}})
+		return q
+	})
+
+	rec := serve(e, http.MethodGet, "/things?_limit=5", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"title":"foo"`)
+
+	rec = serve(e, http.MethodGet, "/things?_limit=abc", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestResource_ListFilterGroup(t *testing.T) {
+	e, repo := newTestResource(t)
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		groups := q.(db.FilteredQueryObject).FilterGroups()
+		assert.Len(t, groups, 1)
+		assert.Equal(t, db.OR, groups[0].Conjunction)
+		assert.Len(t, groups[0].Filters, 2)
+		q.SetResult(&[]MockThing{})
+		return q
+	})
+
+	rec := serve(e, http.MethodGet, "/things?_or=title=foo;views:gt=3", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, http.MethodGet, "/things?_or=title=%zz", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestResource_Get(t *testing.T) {
+	e, repo := newTestResource(t)
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		assert.Equal(t, "1", q.(db.IdentifiedQueryObject).ID())
+		q.SetResult(&MockThing{ID: 1, Title: "foo"})
+		return q
+	})
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		q.SetError(db.NewEntityNotFoundErr("MockThing", "2"))
+		return q
+	})
+
+	rec := serve(e, http.MethodGet, "/things/1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":1,"title":"foo","views":0}`, rec.Body.String())
+
+	rec = serve(e, http.MethodGet, "/things/2", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestResource_Write(t *testing.T) {
+	e, repo := newTestResource(t)
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		wq := q.(db.WriteQueryObject)
+		assert.Equal(t, db.Create, wq.Operation())
+		assert.Equal(t, 0, q.Model().(*MockThing).ID)
+		q.SetResult(q.Model())
+		return q
+	})
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		wq := q.(db.WriteQueryObject)
+		assert.Equal(t, db.Update, wq.Operation())
+		assert.Equal(t, "1", wq.ID())
+		assert.Equal(t, &MockThing{Title: "bar"}, q.Model())
+		q.SetResult(&MockThing{ID: 1, Title: "bar"})
+		return q
+	})
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		wq := q.(db.WriteQueryObject)
+		assert.Equal(t, db.Patch, wq.Operation())
+		assert.Equal(t, map[string]interface{}{"Views": 3}, wq.Values())
+		q.SetResult(&MockThing{ID: 1, Views: 3})
+		return q
+	})
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		assert.Equal(t, db.Delete, q.(db.WriteQueryObject).Operation())
+		return q
+	})
+
+	rec := serve(e, http.MethodPost, "/things", `{"id":5,"title":"foo"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":0,"title":"foo","views":0}`, rec.Body.String())
+
+	rec = serve(e, http.MethodPut, "/things/1", `{"id":2,"title":"bar"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, http.MethodPatch, "/things/1", `{"views":3}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, http.MethodPatch, "/things/1", `{"unknown":3}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(e, http.MethodPatch, "/things/1", `{"id":2}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(e, http.MethodDelete, "/things/1", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+type MockAccount struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Hash  string `json:"hash" api:"-"`
+	Token string `json:"-"`
+}
+
+func TestResource_WriteUnwritableFields(t *testing.T) {
+	conn, err := db.NewDatabase(db.Config{Driver: db.Sqlite, DatabaseName: filepath.Join(t.TempDir(), "resource.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&MockAccount{}))
+	assert.Nil(t, conn.Create(&MockAccount{Name: "foo", Hash: "secret", Token: "token"}).Error)
+
+	responses := rh.NewResponseHandler()
+	responses.Register(MockAccount{}, identity)
+	e := echo.New()
+	NewResource[MockAccount]("/accounts", db.NewQueryManager(conn), responses).Bind(e)
+
+	rec := serve(e, http.MethodPut, "/accounts/1", `{"name":"bar","hash":"x"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = serve(e, http.MethodPost, "/accounts", `{"name":"baz","hash":"x"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	accounts := make([]MockAccount, 0)
+	assert.Nil(t, conn.Order("id").Find(&accounts).Error)
+	assert.Equal(t, []MockAccount{
+		{ID: 1, Name: "bar", Hash: "secret", Token: "token"},
+		{ID: 2, Name: "baz"},
+	}, accounts)
+}
+
+func TestResource_NotAcceptable(t *testing.T) {
+	e, repo := newTestResource(t)
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		q.SetResult(&MockThing{ID: 1, Title: "foo"})
+		return q
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(`{"title":"foo"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, "text/html")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/things/1", nil)
+	req.Header.Set(echo.HeaderAccept, rh.MIMEJSONAPI)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, rh.MIMEJSONAPI, rec.Header().Get(echo.HeaderContentType))
+	assert.JSONEq(t, `{"data":{"type":"mockThing","id":"1","attributes":{"title":"foo","views":0}}}`, rec.Body.String())
+}
+
+func TestResource_Export(t *testing.T) {
+	e, repo := newTestResource(t)
+	rec := serveExport(e, "/things?_limit=2&_fields=title", repo, 2)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, rh.MIMETextCSV, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "title\nt0\nt1\n", rec.Body.String())
+
+	rec = serveExport(e, "/things?_limit=20000", repo, 2)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestResource_ExportAll(t *testing.T) {
+	e := echo.New()
+	repo := mock_db.NewMockRepository(gomock.NewController(t))
+	repo.EXPECT().Supports(gomock.Any()).Return(true).AnyTimes()
+	manager := db.NewQueryManager(nil)
+	manager.Register(repo)
+	responses := rh.NewResponseHandler()
+	responses.Register([]MockThing{}, identity)
+	resource := NewResource[MockThing]("/things", manager, responses)
+	resource.AllowExport(true)
+	resource.Bind(e)
+
+	rec := serveExport(e, "/things?_limit=2500&_fields=id", repo, 2500)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2501, strings.Count(rec.Body.String(), "\n"))
+}
+
+func TestResource_ExportErrors(t *testing.T) {
+	e := echo.New()
+	repo := mock_db.NewMockRepository(gomock.NewController(t))
+	repo.EXPECT().Supports(gomock.Any()).Return(true).AnyTimes()
+	manager := db.NewQueryManager(nil)
+	manager.Register(repo)
+	NewResource[MockThing]("/things", manager, rh.NewResponseHandler()).Bind(e)
+
+	// rows without mapping fail before the response is committed
+	rec := serveExport(e, "/things", repo, 2)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotEqual(t, rh.MIMETextCSV, rec.Header().Get(echo.HeaderContentType))
+
+	e, repo = newTestResource(t)
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		q.SetError(db.NewUnsupportedStreamPreloadErr())
+		return q
+	})
+	req := httptest.NewRequest(http.MethodGet, "/things", nil)
+	req.Header.Set(echo.HeaderAccept, rh.MIMETextCSV)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// serveExport streams rows of consecutive ids up to total for a csv export
+func serveExport(e *echo.Echo, target string, repo *mock_db.MockRepository, total int) *httptest.ResponseRecorder {
+	repo.EXPECT().Handle(gomock.Any()).DoAndReturn(func(q db.QueryObject) db.QueryObject {
+		sq := q.(db.StreamedQueryObject)
+		sel := sq.Slice()
+		things := make([]MockThing, 0)
+		for i := sel.Offset; i < total && (sel.Limit == 0 || len(things) < sel.Limit); i++ {
+			things = append(things, MockThing{ID: i, Title: fmt.Sprintf("t%d", i)})
+		}
+		sq.SetIterator(db.NewSliceIterator(things))
+		return q
+	}).AnyTimes()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set(echo.HeaderAccept, rh.MIMETextCSV)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestPatchValues(t *testing.T) {
+	values, err := patchValues(reflect.TypeOf(MockThing{}), map[string]json.RawMessage{"title": []byte(`"foo"`), "views": []byte(`2`)})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"Title": "foo", "Views": 2}, values)
+
+	_, err = patchValues(reflect.TypeOf(MockThing{}), map[string]json.RawMessage{"views": []byte(`"two"`)})
+	assert.NotNil(t, err)
+
+	type account struct {
+		ID        int       `json:"id"`
+		Name      string    `json:"name"`
+		Hash      string    `json:"hash" api:"-"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	for _, name := range []string{"id", "hash", "created_at"} {
+		_, err = patchValues(reflect.TypeOf(account{}), map[string]json.RawMessage{name: []byte(`"x"`)})
+		assert.EqualError(t, err, fmt.Sprintf("field %s is not writable", name))
+	}
+	values, err = patchValues(reflect.TypeOf(account{}), map[string]json.RawMessage{"name": []byte(`"foo"`)})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"Name": "foo"}, values)
+}
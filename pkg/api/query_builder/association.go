@@ -0,0 +1,64 @@
+package query_builder
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+const pathSeparator = "."
+
+// AllowAssociationFilter allow filtering by fields of the association with the given json path, e.g. "author" or "author.company",
+// nested fields follow the field policies of the associated model: author.name=foo, tags.label:in=a,b
+func (b *QueryBuilder) AllowAssociationFilter(path string) error {
+	if _, _, ok := resolveJSONPath(b.model, path); !ok {
+		return errors.New("invalid association path")
+	}
+
+	b.associations = append(b.associations, path)
+
+	return nil
+}
+
+// associationFilterFields filterable fields of allowed associations keyed by json path
+func (b *QueryBuilder) associationFilterFields() map[string]fieldPolicy {
+	fields := make(map[string]fieldPolicy)
+
+	for _, path := range b.associations {
+		fieldPath, t, _ := resolveJSONPath(b.model, path)
+		for name, policy := range modelFieldPolicies(reflect.New(t).Interface()) {
+			if !policy.filter {
+				continue
+			}
+
+			policy.name = fieldPath + pathSeparator + policy.name
+			fields[path+pathSeparator+name] = policy
+		}
+	}
+
+	return fields
+}
+
+// resolveJSONPath associations ignored by api:"-" are not resolved
+// "author.company" => "Author.Company", type of Company struct
+func resolveJSONPath(model interface{}, path string) (fieldPath string, t reflect.Type, ok bool) {
+	t = getType(model)
+	names := make([]string, 0)
+
+	for _, name := range strings.Split(path, pathSeparator) {
+		if t.Kind() != reflect.Struct {
+			return "", nil, false
+		}
+
+		policy, found := modelFieldPolicies(reflect.New(t).Interface())[name]
+		if !found {
+			return "", nil, false
+		}
+
+		field, _ := t.FieldByName(policy.name)
+		t = traverseType(field.Type)
+		names = append(names, policy.name)
+	}
+
+	return strings.Join(names, pathSeparator), t, t.Kind() == reflect.Struct
+}
@@ -0,0 +1,50 @@
+package query_builder
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type AuthorMockEntity struct {
+	Name    string            `json:"name" api:"filter"`
+	Email   string            `json:"email"`
+	Company *PolicyMockEntity `json:"company"`
+}
+
+type PostMockEntity struct {
+	Title  string           `json:"title"`
+	Author AuthorMockEntity `json:"author"`
+	Tags   []*MockEntity    `json:"tags"`
+	Editor AuthorMockEntity `json:"editor" api:"-"`
+}
+
+func TestQueryBuilder_AllowAssociationFilter(t *testing.T) {
+	builder := NewQueryBuilder(&[]PostMockEntity{})
+
+	assert.Error(t, builder.AllowAssociationFilter("title"))
+	assert.Error(t, builder.AllowAssociationFilter("author.unknown"))
+	assert.Nil(t, builder.AllowAssociationFilter("author"))
+	assert.Nil(t, builder.AllowAssociationFilter("author.company"))
+	assert.Nil(t, builder.AllowAssociationFilter("tags"))
+	assert.Error(t, builder.AllowAssociationFilter("editor"))
+
+	filters, err := builder.buildFilters(url.Values{
+		"author.name":          {"foo"},
+		"author.email":         {"not allowed by author policy"},
+		"author.company.age":   {"3"},
+		"tags.foo:in":          {"a,b"},
+		"unknown.association":  {"skipped"},
+		"author.company.email": {"skipped"},
+		"editor.name":          {"skipped"},
+	}, builder.filterFields())
+
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []db.Filter{
+		{FieldName: "Author.Name", Operator: "=", Value: []string{"foo"}},
+		{FieldName: "Author.Company.Age", Operator: "=", Value: []int{3}},
+		{FieldName: "Tags.Foo", Operator: "IN", Value: []string{"a", "b"}},
+	}, filters)
+}
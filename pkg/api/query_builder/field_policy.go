@@ -0,0 +1,176 @@
+package query_builder
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/mangalores/go-api-skeleton/pkg/utils"
+)
+
+const (
+	apiTag          = "api"
+	apiTagSeparator = ";"
+	apiTagFilter    = "filter"
+	apiTagSort      = "sort"
+	apiTagEmbed     = "embed"
+	apiTagIgnore    = "-"
+)
+
+// fieldPolicy what clients may do with a model field, keyed by its json name
+type fieldPolicy struct {
+	name      string
+	filter    bool
+	operators []string
+	sort      bool
+	embed     bool
+}
+
+// allowsOperator empty operator list allows every operator
+func (p fieldPolicy) allowsOperator(identifier string) bool {
+	return p.filter && (len(p.operators) == 0 || utils.Contains(p.operators, identifier))
+}
+
+// modelFieldPolicies
+// reads policies from api struct tags, e.g.
+//
+//	Name   string `json:"name" api:"filter=eq,ne,contains;sort"`
+//	Author Author `json:"author" api:"embed"`
+//	Hash   string `json:"hash" api:"-"`
+//
+// fields without api tag can neither be filtered nor sorted unless allowed by AllowFilter or AllowSort
+func modelFieldPolicies(model interface{}) map[string]fieldPolicy {
+	policies := make(map[string]fieldPolicy)
+	t := getType(model)
+
+	if t.Kind() != reflect.Struct {
+		return policies
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		name, ok := utils.JSONName(field)
+		if !ok {
+			continue
+		}
+
+		if IgnoredField(field) {
+			continue
+		}
+
+		policies[name] = parseAPITag(field.Name, field.Tag.Get(apiTag))
+	}
+
+	return policies
+}
+
+// IgnoredField fields tagged api:"-" are neither exposed to queries nor writable by clients
+func IgnoredField(field reflect.StructField) bool {
+	return field.Tag.Get(apiTag) == apiTagIgnore
+}
+
+// parseAPITag
+// "filter=eq,lt;sort" => fieldPolicy{filter: true, operators: {"eq", "lt"}, sort: true}
+func parseAPITag(fieldName string, tag string) fieldPolicy {
+	policy := fieldPolicy{name: fieldName}
+
+	for _, option := range strings.Split(tag, apiTagSeparator) {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+
+		switch key {
+		case apiTagFilter:
+			policy.filter = true
+			if value != "" {
+				policy.operators = strings.Split(value, ",")
+			}
+		case apiTagSort:
+			policy.sort = true
+		case apiTagEmbed:
+			policy.embed = true
+		}
+	}
+
+	return policy
+}
+
+// AllowFilter allow filtering by the field with json name, restricted to the given operator identifiers if any,
+// fields ignored by api:"-" cannot be allowed
+func (b *QueryBuilder) AllowFilter(name string, operators ...string) error {
+	policy, err := b.registeredPolicy(name)
+	if err != nil {
+		return err
+	}
+
+	policy.filter = true
+	policy.operators = operators
+	b.policies[name] = policy
+
+	return nil
+}
+
+// AllowSort allow sorting by the field with json name
+func (b *QueryBuilder) AllowSort(name string) error {
+	policy, err := b.registeredPolicy(name)
+	if err != nil {
+		return err
+	}
+
+	policy.sort = true
+	b.policies[name] = policy
+
+	return nil
+}
+
+func (b *QueryBuilder) registeredPolicy(name string) (fieldPolicy, error) {
+	if policy, ok := b.policies[name]; ok {
+		return policy, nil
+	}
+	if policy, ok := modelFieldPolicies(b.model)[name]; ok {
+		return policy, nil
+	}
+
+	return fieldPolicy{}, errors.New("invalid field name")
+}
+
+// fieldPolicies tag policies of the model merged with registered ones
+func (b *QueryBuilder) fieldPolicies() map[string]fieldPolicy {
+	policies := modelFieldPolicies(b.model)
+	for name, policy := range b.policies {
+		policies[name] = policy
+	}
+
+	return policies
+}
+
+// filterFields fields clients may filter by, including fields of allowed associations
+func (b *QueryBuilder) filterFields() map[string]fieldPolicy {
+	fields := b.associationFilterFields()
+	for name, policy := range b.fieldPolicies() {
+		if policy.filter {
+			fields[name] = policy
+		}
+	}
+
+	return fields
+}
+
+// sortFields json name => field name of fields clients may sort by
+func (b *QueryBuilder) sortFields() map[string]string {
+	fields := make(map[string]string)
+	for name, policy := range b.fieldPolicies() {
+		if policy.sort {
+			fields[name] = policy.name
+		}
+	}
+
+	return fields
+}
+
+// selectableFields json name => field name of fields clients may select, every field not ignored by policy
+func (b *QueryBuilder) selectableFields() map[string]string {
+	fields := make(map[string]string)
+	for name, policy := range modelFieldPolicies(b.model) {
+		fields[name] = policy.name
+	}
+
+	return fields
+}
@@ -0,0 +1,98 @@
+package query_builder
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type PolicyMockEntity struct {
+	Name     string       `json:"name" api:"filter=eq,contains;sort"`
+	Age      int          `json:"age,omitempty" api:"filter"`
+	Email    string       `json:"email"`
+	Hash     string       `json:"hash" api:"-"`
+	Children []MockEntity `json:"children" api:"embed"`
+}
+
+type UntaggedMockEntity struct {
+	Foo string `json:"foo"`
+	Bar string `json:"bar"`
+}
+
+func TestModelFieldPolicies(t *testing.T) {
+	policies := modelFieldPolicies(&[]PolicyMockEntity{})
+
+	assert.Equal(t, map[string]fieldPolicy{
+		"name":     {name: "Name", filter: true, operators: []string{"eq", "contains"}, sort: true},
+		"age":      {name: "Age", filter: true},
+		"email":    {name: "Email"},
+		"children": {name: "Children", embed: true},
+	}, policies)
+
+	// models without api tags accept no field for filtering or sorting
+	policies = modelFieldPolicies(&UntaggedMockEntity{})
+
+	assert.Equal(t, map[string]fieldPolicy{
+		"foo": {name: "Foo"},
+		"bar": {name: "Bar"},
+	}, policies)
+
+	builder := NewQueryBuilder(&[]UntaggedMockEntity{})
+	_, err := builder.buildFilters(url.Values{"foo": {"a"}}, builder.filterFields())
+	assert.Nil(t, err)
+	assert.Empty(t, builder.filterFields())
+	assert.Empty(t, builder.sortFields())
+}
+
+func TestQueryBuilder_FieldPolicies(t *testing.T) {
+	builder := NewQueryBuilder(&[]PolicyMockEntity{})
+
+	filters, err := builder.buildFilters(url.Values{"name:contains": {"a"}, "email": {"b"}, "hash": {"c"}}, builder.filterFields())
+	assert.Nil(t, err)
+	assert.Equal(t, []db.Filter{{FieldName: "Name", Operator: "LIKE", Value: []string{"%a%"}}}, filters)
+
+	_, err = builder.buildFilters(url.Values{"name:gt": {"a"}}, builder.filterFields())
+	assert.Equal(t, InvalidFilterErr{db.Filter{FieldName: "name", Operator: ">"}}, err)
+
+	assert.Equal(t, map[string]string{"name": "Name"}, builder.sortFields())
+
+	preloads, err := builder.buildPreloads(url.Values{"_embed": {"children"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []db.Preload{{Name: "Children"}}, preloads)
+
+	_, err = builder.buildPreloads(url.Values{"_embed": {"name"}})
+	assert.Equal(t, NewInvalidEmbedErr("name"), err)
+}
+
+func TestQueryBuilder_RegisteredPolicies(t *testing.T) {
+	builder := NewQueryBuilder(&[]UntaggedMockEntity{})
+
+	assert.Nil(t, builder.AllowFilter("foo", "eq"))
+	assert.Nil(t, builder.AllowSort("bar"))
+	assert.Error(t, builder.AllowSort("baz"))
+
+	assert.Equal(t, map[string]fieldPolicy{"foo": {name: "Foo", filter: true, operators: []string{"eq"}}}, builder.filterFields())
+	assert.Equal(t, map[string]string{"bar": "Bar"}, builder.sortFields())
+
+	// ignored fields cannot be allowed
+	builder = NewQueryBuilder(&[]PolicyMockEntity{})
+	assert.Error(t, builder.AllowFilter("hash"))
+	assert.Error(t, builder.AllowSort("hash"))
+	assert.Nil(t, builder.AllowFilter("email", "eq"))
+	assert.NotContains(t, builder.filterFields(), "hash")
+	assert.Contains(t, builder.filterFields(), "email")
+}
+
+func TestQueryBuilder_MultipleValuesPolicy(t *testing.T) {
+	builder := NewQueryBuilder(&[]PolicyMockEntity{})
+
+	// eq with multiple values is mapped to in, which is not allowed for name
+	_, err := builder.buildFilters(url.Values{"name": {"a", "b"}}, builder.filterFields())
+	assert.Equal(t, InvalidFilterErr{db.Filter{FieldName: "name", Operator: "="}}, err)
+
+	filters, err := builder.buildFilters(url.Values{"age": {"1", "2"}}, builder.filterFields())
+	assert.Nil(t, err)
+	assert.Equal(t, []db.Filter{{FieldName: "Age", Operator: "IN", Value: []int{1, 2}}}, filters)
+}
@@ -0,0 +1,127 @@
+package query_builder
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+)
+
+const (
+	andField       = "_and"
+	orField        = "_or"
+	notField       = "_not"
+	groupSeparator = ';'
+)
+
+var groupFields = []string{andField, orField, notField}
+
+var conjunctionMap = map[string]db.Conjunction{
+	andField: db.AND,
+	orField:  db.OR,
+	notField: db.NOT,
+}
+
+// buildFilterGroups
+// provided query param _or=name:eq=foo;age:gt=3 => []FilterGroup{{Conjunction: OR, Filters: {name = foo, age > 3}}}
+// groups can be nested by wrapping members in a group name: _or=name=foo;_and(age:gt=3;age:lt=10)
+// params have to be parsed by ParseQuery to preserve ';'
+func (b *QueryBuilder) buildFilterGroups(params url.Values, fields map[string]fieldPolicy) ([]db.FilterGroup, error) {
+	groups := make([]db.FilterGroup, 0)
+
+	for _, name := range groupFields {
+		for _, expression := range params[name] {
+			group, err := b.parseFilterGroup(name, conjunctionMap[name], expression, fields)
+			if err != nil {
+				return groups, err
+			}
+
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
+}
+
+func (b *QueryBuilder) parseFilterGroup(param string, conjunction db.Conjunction, expression string, fields map[string]fieldPolicy) (db.FilterGroup, error) {
+	group := db.FilterGroup{Conjunction: conjunction, Filters: []db.Filter{}, Groups: []db.FilterGroup{}}
+
+	members, ok := splitGroupMembers(expression)
+	if !ok || len(members) == 0 {
+		return group, NewInvalidParamValueErr(param, false)
+	}
+
+	for _, member := range members {
+		if name, inner, ok := extractNestedGroup(member); ok {
+			nested, err := b.parseFilterGroup(param, conjunctionMap[name], inner, fields)
+			if err != nil {
+				return group, err
+			}
+
+			group.Groups = append(group.Groups, nested)
+			continue
+		}
+
+		key, value, found := strings.Cut(member, "=")
+		if !found {
+			return group, NewInvalidParamValueErr(param, false)
+		}
+
+		// unlike plain filters, unknown fields can not be skipped without changing the meaning of the group
+		filter, ok, err := b.buildFilter(key, []string{value}, fields)
+		if err != nil {
+			return group, err
+		}
+		if !ok {
+			return group, NewInvalidFilterErr(filter)
+		}
+
+		group.Filters = append(group.Filters, filter)
+	}
+
+	return group, nil
+}
+
+// splitGroupMembers split expression at top level separators, ok is false on unbalanced parentheses
+func splitGroupMembers(expression string) (members []string, ok bool) {
+	depth := 0
+	start := 0
+
+	appendMember := func(member string) {
+		if member != "" {
+			members = append(members, member)
+		}
+	}
+
+	for i, c := range expression {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return members, false
+			}
+		case groupSeparator:
+			if depth == 0 {
+				appendMember(expression[start:i])
+				start = i + 1
+			}
+		}
+	}
+	appendMember(expression[start:])
+
+	return members, depth == 0
+}
+
+// extractNestedGroup
+// _and(a=1;b=2) => "_and", "a=1;b=2", true
+func extractNestedGroup(member string) (name string, inner string, ok bool) {
+	for _, name = range groupFields {
+		if strings.HasPrefix(member, name+"(") && strings.HasSuffix(member, ")") {
+			return name, member[len(name)+1 : len(member)-1], true
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,84 @@
+package query_builder
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFilterGroups(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+	fields := builder.filterFields()
+
+	testCases := []struct {
+		params   url.Values
+		expected []db.FilterGroup
+		err      error
+	}{
+		{
+			url.Values{"_or": {"foo:eq=a;bar:gt=3"}},
+			[]db.FilterGroup{{
+				Conjunction: db.OR,
+				Filters: []db.Filter{
+					{FieldName: "Foo", Operator: "=", Value: []string{"a"}},
+					{FieldName: "Bar", Operator: ">", Value: []string{"3"}},
+				},
+				Groups: []db.FilterGroup{},
+			}},
+			nil,
+		},
+		{
+			// nested groups
+			url.Values{"_or": {"foo=a;_and(bar:gt=3;_not(bar:in=5,6))"}},
+			[]db.FilterGroup{{
+				Conjunction: db.OR,
+				Filters:     []db.Filter{{FieldName: "Foo", Operator: "=", Value: []string{"a"}}},
+				Groups: []db.FilterGroup{{
+					Conjunction: db.AND,
+					Filters:     []db.Filter{{FieldName: "Bar", Operator: ">", Value: []string{"3"}}},
+					Groups: []db.FilterGroup{{
+						Conjunction: db.NOT,
+						Filters:     []db.Filter{{FieldName: "Bar", Operator: "IN", Value: []string{"5", "6"}}},
+						Groups:      []db.FilterGroup{},
+					}},
+				}},
+			}},
+			nil,
+		},
+		{
+			// unbalanced parentheses
+			url.Values{"_or": {"foo=a;_and(bar:gt=3"}},
+			[]db.FilterGroup{},
+			InvalidParamValueErr{name: "_or"},
+		},
+		{
+			// member without value
+			url.Values{"_not": {"foo"}},
+			[]db.FilterGroup{},
+			InvalidParamValueErr{name: "_not"},
+		},
+		{
+			// unknown fields are rejected instead of skipped
+			url.Values{"_or": {"foo=a;baz=b"}},
+			[]db.FilterGroup{},
+			InvalidFilterErr{db.Filter{FieldName: "baz", Operator: "="}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		groups, err := builder.buildFilterGroups(testCase.params, fields)
+		assert.Equal(t, testCase.err, err)
+		assert.Equal(t, testCase.expected, groups)
+	}
+}
+
+func TestSplitGroupMembers(t *testing.T) {
+	members, ok := splitGroupMembers("a=1;_or(b=2;c=3);;d=4")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a=1", "_or(b=2;c=3)", "d=4"}, members)
+
+	_, ok = splitGroupMembers("a=1);(b=2")
+	assert.False(t, ok)
+}
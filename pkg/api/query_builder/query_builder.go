@@ -7,21 +7,31 @@ import (
 	"net/url"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
-const compositePattern = "^(?P<name>[a-zA-Z0-9]+):(?P<op>[a-zA-Z]+)$"
-const plainParamPattern = "^[a-zA-Z0-9]+$"
+const compositePattern = "^(?P<name>[a-zA-Z0-9]+(?:\\.[a-zA-Z0-9]+)*):(?P<op>[a-zA-Z]+)$"
+const plainParamPattern = "^[a-zA-Z0-9]+(?:\\.[a-zA-Z0-9]+)*$"
 const (
 	maxLimit       = 10000
 	defaultLimit   = 500
 	defaultOffset  = 0
 	sortField      = "_sort"
-	limitField     = "_limit"
-	offsetField    = "_offset"
+	countField     = "_count"
 	embedField     = "_embed"
+	fieldsField    = "_fields"
 	reservedPrefix = "_"
 )
 
+// page params, e.g. to build links to other pages of a collection
+const (
+	LimitField  = "_limit"
+	OffsetField = "_offset"
+	CursorField = "_cursor"
+)
+
 var plainRegEx = regexp.MustCompile(plainParamPattern)
 var compositeRegEx = regexp.MustCompile(compositePattern)
 
@@ -34,16 +44,68 @@ var operatorMap = map[string]string{
 	"gte": ">=",
 }
 
+// patternOperatorMap text pattern operators, ILIKE is rendered as LOWER(column) LIKE LOWER(?) on every dialect
+var patternOperatorMap = map[string]string{
+	"like":     "LIKE",
+	"ilike":    "ILIKE",
+	"contains": "LIKE",
+	"starts":   "LIKE",
+	"ends":     "LIKE",
+}
+
+// transformFnMap value transformation for pattern operators
+var transformFnMap = map[string]func(value []string) []string{
+	"like":     LikeTransformFn,
+	"ilike":    LikeTransformFn,
+	"contains": ContainsTransformFn,
+	"starts":   StartsWithTransformFn,
+	"ends":     EndsWithTransformFn,
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+var listOperatorMap = map[string]string{
+	"in":  "IN",
+	"nin": "NOT IN",
+}
+
+// multiValueOperatorMap operators to switch to when a param is given multiple times, e.g. status=a&status=b
+var multiValueOperatorMap = map[string]string{
+	"eq": "in",
+	"ne": "nin",
+}
+
+// customOperatorMap operators building their own sql operator and value from the param values
+var customOperatorMap = map[string]OperatorFn{
+	"null":    NullOperatorFn,
+	"between": BetweenOperatorFn,
+}
+
+// OperatorFn builds the sql operator and filter value from param values
+type OperatorFn func(values []string) (operator string, value interface{}, err error)
+
 type Operator struct {
+	identifier  string
 	operator    string
 	transformFn func(value []string) []string
 	matchField  *regexp.Regexp
+	list        bool
+	coerce      bool
+	buildFn     OperatorFn
+}
+
+func (o *Operator) Identifier() string {
+	return o.identifier
 }
 
 func (o *Operator) Operator() string {
 	return o.operator
 }
 
+func (o *Operator) IsList() bool {
+	return o.list
+}
+
 func (o *Operator) FieldMatches(fieldName string) bool {
 	return o.matchField.MatchString(fieldName)
 }
@@ -52,12 +114,27 @@ func (o *Operator) TransformValue(values []string) interface{} {
 	return o.transformFn(values)
 }
 
+// Build sql operator and filter value from param values
+func (o *Operator) Build(values []string) (string, interface{}, error) {
+	if o.buildFn != nil {
+		return o.buildFn(values)
+	}
+
+	return o.Operator(), o.TransformValue(values), nil
+}
+
 type QueryBuilder struct {
 	operators          map[string]*Operator
 	allowedEmbed       map[string]db.Preload
 	isSlice            bool
+	keyset             bool
+	countStrategy      db.CountStrategy
+	countCap           int64
+	timeout            time.Duration
 	defaultSort        []db.Sort
 	presetFilter       []db.Filter
+	policies           map[string]fieldPolicy
+	associations       []string
 	preload            []db.Preload
 	model              interface{}
 	loadPreloads       bool
@@ -77,8 +154,14 @@ func (b *QueryBuilder) Reset(model interface{}) *QueryBuilder {
 	b.registerDefaultOperators()
 	b.defaultSort = []db.Sort{}
 	b.presetFilter = []db.Filter{}
+	b.policies = make(map[string]fieldPolicy)
+	b.associations = []string{}
+	b.allowedEmbed = make(map[string]db.Preload)
 	b.preload = []db.Preload{}
 	b.loadPreloads = false
+	b.countStrategy = db.CountExact
+	b.countCap = 0
+	b.timeout = 0
 	b.appendedParameters = make(url.Values)
 
 	return b
@@ -88,13 +171,59 @@ func (b *QueryBuilder) registerDefaultOperators() {
 	for identifier, operator := range operatorMap {
 		b.RegisterOperator(identifier, operator, defaultTransformFn, regexp.MustCompile(".*"))
 	}
+	for identifier, operator := range patternOperatorMap {
+		b.RegisterPatternOperator(identifier, operator, transformFnMap[identifier], regexp.MustCompile(".*"))
+	}
+	for identifier, operator := range listOperatorMap {
+		b.RegisterListOperator(identifier, operator, ListTransformFn, regexp.MustCompile(".*"))
+	}
+	for identifier, fn := range customOperatorMap {
+		b.RegisterCustomOperator(identifier, fn, regexp.MustCompile(".*"))
+	}
 }
 
 func (b *QueryBuilder) RegisterOperator(identifier string, operator string, fn func(value []string) []string, rx *regexp.Regexp) {
 	b.operators[identifier] = &Operator{
-		operator,
-		fn,
-		rx,
+		identifier:  identifier,
+		operator:    operator,
+		transformFn: fn,
+		matchField:  rx,
+		coerce:      true,
+	}
+}
+
+// RegisterPatternOperator register an operator matching text patterns, e.g. LIKE, values are not coerced to the field type
+func (b *QueryBuilder) RegisterPatternOperator(identifier string, operator string, fn func(value []string) []string, rx *regexp.Regexp) {
+	b.operators[identifier] = &Operator{
+		identifier:  identifier,
+		operator:    operator,
+		transformFn: fn,
+		matchField:  rx,
+	}
+}
+
+// RegisterListOperator register an operator comparing against a list of values, e.g. IN
+func (b *QueryBuilder) RegisterListOperator(identifier string, operator string, fn func(value []string) []string, rx *regexp.Regexp) {
+	b.operators[identifier] = &Operator{
+		identifier:  identifier,
+		operator:    operator,
+		transformFn: fn,
+		matchField:  rx,
+		list:        true,
+		coerce:      true,
+	}
+}
+
+// RegisterCustomOperator register an operator deciding on sql operator and value itself, e.g. IS NULL / IS NOT NULL,
+// sql operators without a plain "column operator ?" form need a matching db.RegisterCondition
+func (b *QueryBuilder) RegisterCustomOperator(identifier string, fn OperatorFn, rx *regexp.Regexp) {
+	b.operators[identifier] = &Operator{
+		identifier:  identifier,
+		operator:    identifier,
+		transformFn: defaultTransformFn,
+		matchField:  rx,
+		coerce:      true,
+		buildFn:     fn,
 	}
 }
 
@@ -102,6 +231,26 @@ func (b *QueryBuilder) SetSlice(flag bool) {
 	b.isSlice = flag
 }
 
+// SetCountStrategy how totals of collections are counted, clients can skip counting by _count=false
+func (b *QueryBuilder) SetCountStrategy(strategy db.CountStrategy) {
+	b.countStrategy = strategy
+}
+
+// SetCountCap maximum rows counted with db.CountCapped
+func (b *QueryBuilder) SetCountCap(cap int64) {
+	b.countCap = cap
+}
+
+// SetTimeout default timeout of the built queries, no timeout if 0
+func (b *QueryBuilder) SetTimeout(timeout time.Duration) {
+	b.timeout = timeout
+}
+
+// SetCursorPagination use keyset pagination by _cursor instead of _offset for collections
+func (b *QueryBuilder) SetCursorPagination(flag bool) {
+	b.keyset = flag
+}
+
 func (b *QueryBuilder) SetParseEmbedding(flag bool) {
 	b.loadPreloads = flag
 }
@@ -123,7 +272,17 @@ func (b *QueryBuilder) AddPresetFilter(fieldName string, operator string, value
 		return errors.New("invalid field name")
 	}
 
-	b.presetFilter = append(b.presetFilter, db.Filter{FieldName: fieldName, Operator: op.Operator(), Value: value})
+	filter := db.Filter{FieldName: fieldName, Operator: op.Operator(), Value: value}
+
+	// custom operators decide on the sql operator by value
+	if values, ok := value.([]string); ok && op.buildFn != nil {
+		var err error
+		if filter.Operator, filter.Value, err = op.Build(values); err != nil {
+			return err
+		}
+	}
+
+	b.presetFilter = append(b.presetFilter, filter)
 
 	return nil
 }
@@ -175,11 +334,20 @@ func (b *QueryBuilder) buildQuery(params url.Values) (*db.Query, error) {
 		return q, errors.New("model struct/slice must be set")
 	}
 	q.SetModel(b.model)
+	q.SetTimeout(b.timeout)
 
 	preloads, err := b.buildPreloads(params)
 	if len(preloads) > 0 {
 		q.SetPreloads(&preloads)
 	}
+	if err != nil {
+		return q, err
+	}
+
+	fields, err := extractFieldsParamValues(params, b.selectableFields())
+	if len(fields) > 0 {
+		q.SetFields(fields)
+	}
 
 	return q, err
 }
@@ -188,7 +356,9 @@ func (b *QueryBuilder) buildFilterQuery(params url.Values, query *db.Query) (*db
 	filters := make([]db.Filter, 0)
 	filterQuery := &db.FilterQuery{Query: *query}
 
-	paramFilters, err := b.buildFilters(b.extendParameters(params), acceptedFilterFields(b.model))
+	fields := b.filterFields()
+
+	paramFilters, err := b.buildFilters(b.extendParameters(params), fields)
 	if err != nil {
 		return filterQuery, err
 	}
@@ -197,6 +367,19 @@ func (b *QueryBuilder) buildFilterQuery(params url.Values, query *db.Query) (*db
 
 	filterQuery.SetFilters(filters)
 
+	groups, err := b.buildFilterGroups(params, fields)
+	if err != nil {
+		return filterQuery, err
+	}
+
+	expressionGroups, err := b.buildFilterExpressions(params, fields)
+	if err != nil {
+		return filterQuery, err
+	}
+	groups = append(groups, expressionGroups...)
+
+	filterQuery.SetFilterGroups(groups)
+
 	return filterQuery, err
 }
 
@@ -211,7 +394,7 @@ func (b *QueryBuilder) buildSlicedQuery(params url.Values, query *db.FilterQuery
 		return cq, err
 	}
 
-	slice.Sort, err = extractSortParamValues(params, acceptedFilterFields(b.model))
+	slice.Sort, err = extractSortParamValues(params, b.sortFields())
 	if err != nil {
 		return cq, err
 	}
@@ -233,75 +416,125 @@ func (b *QueryBuilder) extendParameters(params url.Values) url.Values {
 	return params
 }
 
-func (b *QueryBuilder) buildFilters(params url.Values, fields map[string]string) ([]db.Filter, error) {
+func (b *QueryBuilder) buildFilters(params url.Values, fields map[string]fieldPolicy) ([]db.Filter, error) {
 	filters := make([]db.Filter, 0)
 
 	params = stripReservedFields(params)
 
 	for key, value := range params {
-		var (
-			ok        bool
-			fieldName string
-			operator  *Operator
-		)
-
-		filter := db.Filter{}
+		filter, ok, err := b.buildFilter(key, value, fields)
+		if err != nil {
+			return filters, err
+		}
 
-		fieldName, operator, ok = b.extractParamAndOperator(key)
+		// skip fields not in allowed list
 		if !ok {
-			return filters, NewInvalidFilterErr(filter)
+			continue
 		}
 
-		// todo allow multiple as or list
-		if len(value) > 1 {
-			return filters, NewInvalidMultipleValuesErr(filter)
-		}
+		filters = append(filters, filter)
+	}
 
-		// skip fields not in allowed list
-		_, ok = fields[fieldName]
-		if !ok || !operator.FieldMatches(fieldName) {
-			continue
+	return filters, nil
+}
+
+// buildFilter create filter for a single param key and its values, ok is false if the field is not accepted for filtering
+func (b *QueryBuilder) buildFilter(key string, value []string, fields map[string]fieldPolicy) (filter db.Filter, ok bool, err error) {
+	return b.buildListFilter(key, value, false, fields)
+}
+
+// buildListFilter listed values are items of a list already, e.g. RSQL arguments, list operators use them as is
+// name=in=("a,b",'c') => name IN (a,b, c) instead of splitting them by comma again
+func (b *QueryBuilder) buildListFilter(key string, value []string, listed bool, fields map[string]fieldPolicy) (filter db.Filter, ok bool, err error) {
+	var (
+		fieldName string
+		operator  *Operator
+	)
+
+	fieldName, operator, ok = b.extractParamAndOperator(key)
+	if !ok {
+		return filter, false, NewInvalidFilterErr(filter)
+	}
+
+	filter.FieldName = fieldName
+	filter.Operator = operator.Operator()
+
+	// multiple values are combined into a list filter
+	if len(value) > 1 && !operator.IsList() {
+		if operator, ok = b.mapListOperator(operator); !ok {
+			return filter, false, NewInvalidMultipleValuesErr(filter)
 		}
+	}
 
-		filter.FieldName = fields[fieldName]
+	policy, ok := fields[fieldName]
+	if !ok || !operator.FieldMatches(fieldName) {
+		return filter, false, nil
+	}
+	// checked after mapping, eq with multiple values needs the list operator to be allowed
+	if !policy.allowsOperator(operator.Identifier()) {
+		return filter, false, NewInvalidFilterErr(filter)
+	}
 
-		// apply transformation function to values
-		filter.Value = operator.TransformValue(value)
-		filter.Operator = operator.Operator()
+	// apply transformation function to values
+	if listed && operator.IsList() && operator.buildFn == nil {
+		filter.Operator, filter.Value = operator.Operator(), value
+	} else if filter.Operator, filter.Value, err = operator.Build(value); err != nil {
+		return filter, false, NewInvalidParamValueErr(key, false)
+	}
+	if v, ok := filter.Value.([]string); ok && len(v) == 0 {
+		return filter, false, NewInvalidFilterErr(filter)
+	}
 
-		filters = append(filters, filter)
+	// parse values into the type of the model field
+	if v, ok := filter.Value.([]string); ok && operator.coerce {
+		if filter.Value, err = coerceValues(fieldType(b.model, policy.name), v); err != nil {
+			var typeErr valueTypeErr
+			if errors.As(err, &typeErr) {
+				return filter, false, typeErr.paramErr(fieldName)
+			}
+			return filter, false, NewInvalidParamValueErr(fieldName, false)
+		}
 	}
+	// the query filters by field name, errors above report the json name requested by the client
+	filter.FieldName = policy.name
 
-	return filters, nil
+	return filter, true, nil
 }
 
 func (b *QueryBuilder) buildPreloads(params url.Values) ([]db.Preload, error) {
+	preloads := append([]db.Preload{}, b.preload...)
+
 	if list := params[embedField]; len(list) > 0 {
+		policies := b.fieldPolicies()
+
 		for _, name := range list {
 			preload, ok := b.allowedEmbed[name]
+			if policy := policies[name]; !ok && policy.embed {
+				preload, ok = db.Preload{Name: policy.name}, true
+			}
 			if !ok {
-				return b.preload, NewInvalidEmbedErr(name)
+				return preloads, NewInvalidEmbedErr(name)
 			}
 
-			b.preload = append(b.preload, preload)
+			preloads = append(preloads, preload)
 		}
 	}
 
-	return b.preload, nil
+	return preloads, nil
 }
 
 func (b *QueryBuilder) buildSlice(params url.Values) (slice *db.Slice, err error) {
 	offset := defaultOffset
 	limit := defaultLimit
 
-	offset, err = extractNumericParamValue(params, offsetField)
+	offset, err = extractNumericParamValue(params, OffsetField)
 	if err != nil {
-		return &db.Slice{Offset: defaultOffset, Limit: defaultLimit}, NewInvalidParamValueErr(offsetField, true)
+		return &db.Slice{Offset: defaultOffset, Limit: defaultLimit}, NewInvalidParamValueErr(OffsetField, true)
 	}
 
-	limit, err = extractNumericParamValue(params, limitField)
+	limit, err = extractNumericParamValue(params, LimitField)
 	if err != nil {
-		return &db.Slice{Offset: defaultOffset, Limit: defaultLimit}, NewInvalidParamValueErr(limitField, true)
+		return &db.Slice{Offset: defaultOffset, Limit: defaultLimit}, NewInvalidParamValueErr(LimitField, true)
 	}
 
 	if limit > maxLimit {
@@ -311,7 +544,51 @@ func (b *QueryBuilder) buildSlice(params url.Values) (slice *db.Slice, err error
 		limit = defaultLimit
 	}
 
-	return &db.Slice{Offset: offset, Limit: limit}, nil
+	if list, ok := params[CursorField]; ok || b.keyset {
+		return b.buildKeysetSlice(list, limit)
+	}
+
+	count, err := b.extractCountStrategy(params)
+	if err != nil {
+		return &db.Slice{Offset: defaultOffset, Limit: defaultLimit}, err
+	}
+
+	return &db.Slice{Offset: offset, Limit: limit, Count: count, CountCap: b.countCap}, nil
+}
+
+// extractCountStrategy
+// _count=false skips counting, _count=true or no param uses the configured strategy
+func (b *QueryBuilder) extractCountStrategy(params url.Values) (db.CountStrategy, error) {
+	list, ok := params[countField]
+	if !ok {
+		return b.countStrategy, nil
+	}
+
+	count, err := strconv.ParseBool(list[0])
+	if err != nil {
+		return b.countStrategy, NewInvalidParamValueErr(countField, false)
+	}
+	if !count {
+		return db.CountNone, nil
+	}
+
+	return b.countStrategy, nil
+}
+
+// buildKeysetSlice cursor pagination, a cursor param switches to cursor pagination even if not enabled by default
+func (b *QueryBuilder) buildKeysetSlice(cursors []string, limit int) (*db.Slice, error) {
+	slice := &db.Slice{Limit: limit, Keyset: true}
+	if len(cursors) == 0 || cursors[0] == "" {
+		return slice, nil
+	}
+
+	cursor, err := db.DecodeCursor(cursors[0])
+	if err != nil {
+		return slice, NewInvalidParamValueErr(CursorField, false)
+	}
+	slice.Cursor = cursor
+
+	return slice, nil
 }
 
 func (b *QueryBuilder) extractParamAndOperator(param string) (string, *Operator, bool) {
@@ -340,14 +617,102 @@ func (b *QueryBuilder) mapOperator(v string) (o *Operator, ok bool) {
 	return nil, false
 }
 
+func (b *QueryBuilder) mapListOperator(o *Operator) (*Operator, bool) {
+	identifier, ok := multiValueOperatorMap[o.Identifier()]
+	if !ok {
+		return nil, false
+	}
+
+	return b.mapOperator(identifier)
+}
+
 func defaultTransformFn(value []string) []string {
 	return value
 }
 
+// SearchTransformFN prefix search, same as StartsWithTransformFn
 func SearchTransformFN(value []string) []string {
+	return StartsWithTransformFn(value)
+}
+
+// NullOperatorFn null check, e.g. field:null=true => IS NULL, field:null=false => IS NOT NULL
+func NullOperatorFn(values []string) (string, interface{}, error) {
+	if len(values) != 1 {
+		return "", nil, errors.New("null check requires exactly one value")
+	}
+
+	isNull, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return "", nil, err
+	}
+	if isNull {
+		return db.IsNull, nil, nil
+	}
+
+	return db.IsNotNull, nil, nil
+}
+
+// BetweenOperatorFn inclusive range, e.g. field:between=1,5 => BETWEEN 1 AND 5
+func BetweenOperatorFn(values []string) (string, interface{}, error) {
+	bounds := ListTransformFn(values)
+	if len(bounds) != 2 {
+		return "", nil, errors.New("between requires exactly two values")
+	}
+
+	return db.Between, bounds, nil
+}
+
+// LikeTransformFn escapes LIKE wildcards in values and uses * as wildcard instead, e.g. "a_b*" => "a\_b%"
+func LikeTransformFn(value []string) []string {
+	return transformLikePattern(value, func(v string) string {
+		return strings.ReplaceAll(EscapeLikePattern(v), "*", "%")
+	})
+}
+
+// ContainsTransformFn matches escaped values anywhere in the field, e.g. "50%" => "%50\%%"
+func ContainsTransformFn(value []string) []string {
+	return transformLikePattern(value, func(v string) string {
+		return "%" + EscapeLikePattern(v) + "%"
+	})
+}
+
+// StartsWithTransformFn matches escaped values at the beginning of the field
+func StartsWithTransformFn(value []string) []string {
+	return transformLikePattern(value, func(v string) string {
+		return EscapeLikePattern(v) + "%"
+	})
+}
+
+// EndsWithTransformFn matches escaped values at the end of the field
+func EndsWithTransformFn(value []string) []string {
+	return transformLikePattern(value, func(v string) string {
+		return "%" + EscapeLikePattern(v)
+	})
+}
+
+// EscapeLikePattern escapes LIKE wildcards and the escape character itself
+func EscapeLikePattern(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+func transformLikePattern(value []string, fn func(v string) string) []string {
+	transformed := make([]string, 0, len(value))
+	for _, v := range value {
+		transformed = append(transformed, fn(v))
+	}
+	return transformed
+}
+
+// ListTransformFn splits comma separated values into a flat list, e.g. {"a,b", "c"} => {"a", "b", "c"}
+func ListTransformFn(value []string) []string {
 	transformed := make([]string, 0)
 	for _, v := range value {
-		transformed = append(transformed, v+"%")
+		for _, item := range strings.Split(v, ",") {
+			if item == "" {
+				continue
+			}
+			transformed = append(transformed, item)
+		}
 	}
 	return transformed
 }
@@ -1,8 +1,12 @@
 package query_builder
 
 import (
+	"encoding/json"
+	"github.com/mangalores/go-api-skeleton/pkg/db"
 	"github.com/stretchr/testify/assert"
+	"net/url"
 	"testing"
+	"time"
 )
 
 func TestExtractParamAndOperator(t *testing.T) {
@@ -44,3 +48,189 @@ func TestDefaultTransformFn(t *testing.T) {
 
 	assert.Equal(t, values, actual)
 }
+
+func TestBuildFilters_ListValues(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+	fields := builder.filterFields()
+
+	testCases := []struct {
+		params   url.Values
+		expected []db.Filter
+		err      error
+	}{
+		{
+			// repeated param becomes IN list
+			url.Values{"foo": {"a", "b"}},
+			[]db.Filter{{FieldName: "Foo", Operator: "IN", Value: []string{"a", "b"}}},
+			nil,
+		},
+		{
+			// repeated negated param becomes NOT IN list
+			url.Values{"foo:ne": {"a", "b"}},
+			[]db.Filter{{FieldName: "Foo", Operator: "NOT IN", Value: []string{"a", "b"}}},
+			nil,
+		},
+		{
+			// explicit comma separated list
+			url.Values{"foo:in": {"a,b"}},
+			[]db.Filter{{FieldName: "Foo", Operator: "IN", Value: []string{"a", "b"}}},
+			nil,
+		},
+		{
+			url.Values{"foo:nin": {"a,b", "c"}},
+			[]db.Filter{{FieldName: "Foo", Operator: "NOT IN", Value: []string{"a", "b", "c"}}},
+			nil,
+		},
+		{
+			// empty list
+			url.Values{"foo:in": {","}},
+			[]db.Filter{},
+			InvalidFilterErr{db.Filter{FieldName: "foo", Operator: "IN", Value: []string{}}},
+		},
+		{
+			// multiple values for operator without list counterpart
+			url.Values{"foo:gt": {"a", "b"}},
+			[]db.Filter{},
+			InvalidMultipleValuesErr{db.Filter{FieldName: "foo", Operator: ">"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		filters, err := builder.buildFilters(testCase.params, fields)
+		assert.Equal(t, testCase.err, err)
+		assert.Equal(t, testCase.expected, filters)
+	}
+}
+
+func TestListTransformFn(t *testing.T) {
+	actual := ListTransformFn([]string{"foo,bar", "baz", ""})
+
+	assert.Equal(t, []string{"foo", "bar", "baz"}, actual)
+}
+
+func TestLikeTransformFns(t *testing.T) {
+	values := []string{`50%_off\`, "a*b"}
+
+	assert.Equal(t, []string{`50\%\_off\\`, "a%b"}, LikeTransformFn(values))
+	assert.Equal(t, []string{`%50\%\_off\\%`, "%a*b%"}, ContainsTransformFn(values))
+	assert.Equal(t, []string{`50\%\_off\\%`, "a*b%"}, StartsWithTransformFn(values))
+	assert.Equal(t, []string{`%50\%\_off\\`, "%a*b"}, EndsWithTransformFn(values))
+	assert.Equal(t, StartsWithTransformFn(values), SearchTransformFN(values))
+}
+
+func TestBuildFilters_TextOperators(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+	fields := builder.filterFields()
+
+	filters, err := builder.buildFilters(url.Values{"foo:contains": {"a_b"}}, fields)
+	assert.Nil(t, err)
+	assert.Equal(t, []db.Filter{{FieldName: "Foo", Operator: "LIKE", Value: []string{`%a\_b%`}}}, filters)
+
+	filters, err = builder.buildFilters(url.Values{"foo:ilike": {"a*"}}, fields)
+	assert.Nil(t, err)
+	assert.Equal(t, []db.Filter{{FieldName: "Foo", Operator: "ILIKE", Value: []string{"a%"}}}, filters)
+}
+
+func TestBuildFilters_CustomOperators(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+	fields := builder.filterFields()
+
+	testCases := []struct {
+		params   url.Values
+		expected []db.Filter
+		err      error
+	}{
+		{
+			url.Values{"foo:null": {"true"}},
+			[]db.Filter{{FieldName: "Foo", Operator: db.IsNull}},
+			nil,
+		},
+		{
+			url.Values{"foo:null": {"false"}},
+			[]db.Filter{{FieldName: "Foo", Operator: db.IsNotNull}},
+			nil,
+		},
+		{
+			url.Values{"foo:null": {"maybe"}},
+			[]db.Filter{},
+			InvalidParamValueErr{name: "foo:null"},
+		},
+		{
+			url.Values{"foo:between": {"a,b"}},
+			[]db.Filter{{FieldName: "Foo", Operator: db.Between, Value: []string{"a", "b"}}},
+			nil,
+		},
+		{
+			url.Values{"foo:between": {"a,b,c"}},
+			[]db.Filter{},
+			InvalidParamValueErr{name: "foo:between"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		filters, err := builder.buildFilters(testCase.params, fields)
+		assert.Equal(t, testCase.err, err)
+		assert.Equal(t, testCase.expected, filters)
+	}
+}
+
+func TestAddPresetFilter_CustomOperator(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+
+	err := builder.AddPresetFilter("Foo", "null", []string{"false"})
+	assert.Nil(t, err)
+	assert.Equal(t, []db.Filter{{FieldName: "Foo", Operator: db.IsNotNull}}, builder.presetFilter)
+}
+
+func TestBuildSlice_Cursor(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+
+	slice, err := builder.buildSlice(url.Values{"_limit": {"10"}})
+	assert.Nil(t, err)
+	assert.False(t, slice.Keyset)
+
+	builder.SetCursorPagination(true)
+	slice, err = builder.buildSlice(url.Values{"_limit": {"10"}, "_offset": {"20"}})
+	assert.Nil(t, err)
+	assert.Equal(t, &db.Slice{Limit: 10, Keyset: true}, slice)
+
+	cursor, _ := db.EncodeCursor(db.Cursor{Fields: []string{"Foo"}, Values: []json.RawMessage{[]byte(`"a"`)}})
+	slice, err = builder.buildSlice(url.Values{"_cursor": {cursor}})
+	assert.Nil(t, err)
+	assert.Equal(t, &db.Cursor{Fields: []string{"Foo"}, Values: []json.RawMessage{[]byte(`"a"`)}}, slice.Cursor)
+
+	_, err = builder.buildSlice(url.Values{"_cursor": {"invalid"}})
+	assert.Equal(t, NewInvalidParamValueErr("_cursor", false), err)
+}
+
+func TestBuildSlice_Count(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+
+	slice, err := builder.buildSlice(url.Values{})
+	assert.Nil(t, err)
+	assert.Equal(t, db.CountExact, slice.Count)
+
+	builder.SetCountStrategy(db.CountCapped)
+	builder.SetCountCap(500)
+	slice, err = builder.buildSlice(url.Values{"_count": {"true"}})
+	assert.Nil(t, err)
+	assert.Equal(t, db.CountCapped, slice.Count)
+	assert.Equal(t, int64(500), slice.CountCap)
+
+	slice, err = builder.buildSlice(url.Values{"_count": {"false"}})
+	assert.Nil(t, err)
+	assert.Equal(t, db.CountNone, slice.Count)
+
+	_, err = builder.buildSlice(url.Values{"_count": {"maybe"}})
+	assert.Equal(t, NewInvalidParamValueErr("_count", false), err)
+}
+
+func TestBuild_Timeout(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+	builder.SetSlice(true)
+	builder.SetTimeout(time.Second)
+
+	query, err := builder.Build(url.Values{})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, query.Timeout())
+}
@@ -7,14 +7,23 @@ import (
 )
 
 type InvalidParamValueErr struct {
-	name    string
-	numeric bool
+	name     string
+	numeric  bool
+	expected string
 }
 
 func NewInvalidParamValueErr(name string, numeric bool) InvalidParamValueErr {
 	return InvalidParamValueErr{
-		name,
-		numeric,
+		name:    name,
+		numeric: numeric,
+	}
+}
+
+// NewInvalidTypedParamValueErr value of param name could not be parsed into the expected type
+func NewInvalidTypedParamValueErr(name string, expected string) InvalidParamValueErr {
+	return InvalidParamValueErr{
+		name:     name,
+		expected: expected,
 	}
 }
 
@@ -23,10 +32,17 @@ func (e InvalidParamValueErr) Error() string {
 	if e.numeric {
 		msg = msg + " must be numeric"
 	}
+	if e.expected != "" {
+		msg = msg + " must be of type " + e.expected
+	}
 
 	return msg
 }
 
+func (e InvalidParamValueErr) Name() string {
+	return e.name
+}
+
 type MaxLimitExceededErr struct {
 	maxLimit int
 }
@@ -41,6 +57,10 @@ func (e MaxLimitExceededErr) Error() string {
 	return fmt.Sprintf("collection limit  exceeded (max limit: %d)", e.maxLimit)
 }
 
+func (e MaxLimitExceededErr) Name() string {
+	return LimitField
+}
+
 type InvalidFilterErr struct {
 	filter db.Filter
 }
@@ -55,6 +75,10 @@ func (e InvalidFilterErr) Error() string {
 	return fmt.Sprintf("invalid filter value for name: %s and operator %s", e.filter.FieldName, e.filter.Operator)
 }
 
+func (e InvalidFilterErr) Name() string {
+	return e.filter.FieldName
+}
+
 type InvalidMultipleValuesErr struct {
 	filter db.Filter
 }
@@ -69,6 +93,10 @@ func (e InvalidMultipleValuesErr) Error() string {
 	return fmt.Sprintf("multiple values not allowed for filter withr name: %s and operator %s", e.filter.FieldName, e.filter.Operator)
 }
 
+func (e InvalidMultipleValuesErr) Name() string {
+	return e.filter.FieldName
+}
+
 type InvalidEmbedErr struct {
 	invalidName string
 }
@@ -82,3 +110,29 @@ func NewInvalidEmbedErr(invalidName string) InvalidEmbedErr {
 func (e InvalidEmbedErr) Error() string {
 	return fmt.Sprintf("invalid embed name requested: %s", e.invalidName)
 }
+
+func (e InvalidEmbedErr) Name() string {
+	return embedField
+}
+
+type InvalidFilterExpressionErr struct {
+	expression string
+	position   int
+	reason     string
+}
+
+func NewInvalidFilterExpressionErr(expression string, position int, reason string) InvalidFilterExpressionErr {
+	return InvalidFilterExpressionErr{
+		expression,
+		position,
+		reason,
+	}
+}
+
+func (e InvalidFilterExpressionErr) Error() string {
+	return fmt.Sprintf("invalid filter expression '%s' at position %d: %s", e.expression, e.position, e.reason)
+}
+
+func (e InvalidFilterExpressionErr) Name() string {
+	return filterField
+}
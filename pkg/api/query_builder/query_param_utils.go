@@ -6,14 +6,42 @@ import (
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/mangalores/go-api-skeleton/pkg/utils"
 )
 
 const sortParamPattern = "^(?P<name>[a-zA-Z0-9]+):(?P<direction>([a-zA-Z0-9]+))$"
 
 var sortRegEx = regexp.MustCompile(sortParamPattern)
 
+// ParseQuery parses a raw url query like url.ParseQuery but keeps ';' as it separates filter group members and
+// RSQL constraints, url.ParseQuery drops pairs containing it
+// _or=name=foo;age:gt=3&_limit=5 => url.Values{"_or": {"name=foo;age:gt=3"}, "_limit": {"5"}}
+func ParseQuery(rawQuery string) (url.Values, error) {
+	params := make(url.Values)
+
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			return params, NewInvalidParamValueErr(key, false)
+		}
+		if value, err = url.QueryUnescape(value); err != nil {
+			return params, NewInvalidParamValueErr(name, false)
+		}
+
+		params[name] = append(params[name], value)
+	}
+
+	return params, nil
+}
+
 // extractSortParamValues
 // provided query param _sort=fieldA:asc&_sort=fieldB:desc => []Sort{{fieldName:"fieldA",direction:"ASC"},{fieldName:"fieldB",direction:"DESC"}}
 func extractSortParamValues(params url.Values, fields map[string]string) ([]db.Sort, error) {
@@ -65,6 +93,24 @@ func extractNumericParamValue(params url.Values, name string) (value int, err er
 	return value, err
 }
 
+// extractFieldsParamValues
+// provided query param _fields=foo,bar => []string{"Foo", "Bar"}
+func extractFieldsParamValues(params url.Values, fields map[string]string) ([]string, error) {
+	selected := make([]string, 0)
+
+	for _, name := range ListTransformFn(params[fieldsField]) {
+		fieldName, ok := fields[strings.TrimSpace(name)]
+		if !ok {
+			return selected, NewInvalidParamValueErr(fieldsField, false)
+		}
+		if !utils.Contains(selected, fieldName) {
+			selected = append(selected, fieldName)
+		}
+	}
+
+	return selected, nil
+}
+
 func stripReservedFields(values url.Values) url.Values {
 	reserved := regexp.MustCompile(fmt.Sprintf("^%s[a-zA-Z0-9]+$", reservedPrefix))
 	params := make(url.Values)
@@ -79,25 +125,6 @@ func stripReservedFields(values url.Values) url.Values {
 	return params
 }
 
-func acceptedFilterFields(i interface{}) map[string]string {
-	fields := make(map[string]string)
-	t := getType(i)
-
-	if t.Kind() != reflect.Struct {
-		return fields
-	}
-	for _, field := range reflect.VisibleFields(t) {
-		tag := field.Tag.Get("json")
-
-		if tag != "" {
-			fieldName := field.Name
-			fields[tag] = fieldName
-		}
-	}
-
-	return fields
-}
-
 func getType(i interface{}) reflect.Type {
 	return traverseType(reflect.TypeOf(i))
 }
@@ -8,11 +8,32 @@ import (
 )
 
 type MockEntity struct {
-	Foo string `json:"foo"`
-	Bar string `json:"bar"`
+	Foo string `json:"foo" api:"filter;sort"`
+	Bar string `json:"bar" api:"filter;sort"`
 	Baz string
 }
 
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected url.Values
+		err      error
+	}{
+		{"", url.Values{}, nil},
+		{"_or=name=foo;age:gt=3&_limit=5", url.Values{"_or": {"name=foo;age:gt=3"}, "_limit": {"5"}}, nil},
+		{"_or=name=foo%3Bage:gt=3", url.Values{"_or": {"name=foo;age:gt=3"}}, nil},
+		{"_filter=name==%22a+b%22;age=gt=3", url.Values{"_filter": {`name=="a b";age=gt=3`}}, nil},
+		{"name=foo&name=bar&&flag", url.Values{"name": {"foo", "bar"}, "flag": {""}}, nil},
+		{"name=%zz", url.Values{}, NewInvalidParamValueErr("name", false)},
+	}
+
+	for _, test := range tests {
+		params, err := ParseQuery(test.query)
+		assert.Equal(t, test.err, err, test.query)
+		assert.Equal(t, test.expected, params, test.query)
+	}
+}
+
 func TestExtractSortParamValues(t *testing.T) {
 	acceptedFields := map[string]string{"foo": "Foo", "bar": "Bar"}
 	testCases := [][]interface{}{
@@ -67,3 +88,14 @@ func Test_GetType(t *testing.T) {
 	getType(&[]*struct{}{})
 
 }
+
+func TestExtractFieldsParamValues(t *testing.T) {
+	acceptedFields := map[string]string{"foo": "Foo", "bar": "Bar"}
+
+	fields, err := extractFieldsParamValues(url.Values{"_fields": {"foo,bar", "foo"}}, acceptedFields)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Foo", "Bar"}, fields)
+
+	_, err = extractFieldsParamValues(url.Values{"_fields": {"foo,baz"}}, acceptedFields)
+	assert.Equal(t, InvalidParamValueErr{name: "_fields"}, err)
+}
@@ -0,0 +1,306 @@
+package query_builder
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+)
+
+const filterField = "_filter"
+
+// rsqlOperatorMap maps RSQL/FIQL comparison operators to operator identifiers,
+// any other operator of the form =identifier= is looked up as registered operator directly
+var rsqlOperatorMap = map[string]string{
+	"==":    "eq",
+	"!=":    "ne",
+	"<":     "lt",
+	"=lt=":  "lt",
+	"<=":    "lte",
+	"=le=":  "lte",
+	">":     "gt",
+	"=gt=":  "gt",
+	">=":    "gte",
+	"=ge=":  "gte",
+	"=in=":  "in",
+	"=out=": "nin",
+}
+
+// rsqlNode either a comparison (selector set) or a group of child nodes combined by conjunction
+type rsqlNode struct {
+	conjunction db.Conjunction
+	children    []rsqlNode
+	selector    string
+	operator    string
+	arguments   []string
+}
+
+func (n rsqlNode) isComparison() bool {
+	return n.selector != ""
+}
+
+// buildFilterExpressions
+// provided query param _filter=name==foo;(age=gt=3,status=in=(a,b)) => []FilterGroup{{Conjunction: AND, Filters: {name = foo}, Groups: {{Conjunction: OR, Filters: {age > 3, status IN (a,b)}}}}}
+// params have to be parsed by ParseQuery to preserve the unencoded ";" of the expression
+func (b *QueryBuilder) buildFilterExpressions(params url.Values, fields map[string]fieldPolicy) ([]db.FilterGroup, error) {
+	groups := make([]db.FilterGroup, 0)
+
+	for _, expression := range params[filterField] {
+		node, err := parseRSQL(expression)
+		if err != nil {
+			return groups, err
+		}
+
+		if node.isComparison() {
+			node = rsqlNode{conjunction: db.AND, children: []rsqlNode{node}}
+		}
+
+		group, err := b.compileRSQL(node, fields)
+		if err != nil {
+			return groups, err
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+func (b *QueryBuilder) compileRSQL(node rsqlNode, fields map[string]fieldPolicy) (db.FilterGroup, error) {
+	group := db.FilterGroup{Conjunction: node.conjunction, Filters: []db.Filter{}, Groups: []db.FilterGroup{}}
+
+	for _, child := range node.children {
+		if !child.isComparison() {
+			nested, err := b.compileRSQL(child, fields)
+			if err != nil {
+				return group, err
+			}
+
+			group.Groups = append(group.Groups, nested)
+			continue
+		}
+
+		identifier, ok := rsqlOperatorMap[child.operator]
+		if !ok {
+			identifier = strings.Trim(child.operator, "=")
+		}
+
+		filter, ok, err := b.buildListFilter(child.selector+":"+identifier, child.arguments, true, fields)
+		if err != nil {
+			return group, err
+		}
+		if !ok {
+			return group, NewInvalidFilterErr(filter)
+		}
+
+		group.Filters = append(group.Filters, filter)
+	}
+
+	return group, nil
+}
+
+// parseRSQL parses an RSQL/FIQL expression:
+//
+//	or         = and { "," and }
+//	and        = constraint { ";" constraint }
+//	constraint = "(" or ")" | comparison
+//	comparison = selector operator ( value | "(" value { "," value } ")" )
+//	value      = unreserved+ | '"' chars '"' | "'" chars "'"
+func parseRSQL(expression string) (rsqlNode, error) {
+	p := &rsqlParser{input: expression}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return node, err
+	}
+	if !p.eof() {
+		return node, p.error("unexpected character")
+	}
+
+	return node, nil
+}
+
+type rsqlParser struct {
+	input string
+	pos   int
+}
+
+func (p *rsqlParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *rsqlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *rsqlParser) error(reason string) InvalidFilterExpressionErr {
+	return NewInvalidFilterExpressionErr(p.input, p.pos, reason)
+}
+
+func (p *rsqlParser) parseOr() (rsqlNode, error) {
+	return p.parseList(db.OR, ',', p.parseAnd)
+}
+
+func (p *rsqlParser) parseAnd() (rsqlNode, error) {
+	return p.parseList(db.AND, ';', p.parseConstraint)
+}
+
+func (p *rsqlParser) parseList(conjunction db.Conjunction, separator byte, parseFn func() (rsqlNode, error)) (rsqlNode, error) {
+	node, err := parseFn()
+	if err != nil || p.peek() != separator {
+		return node, err
+	}
+
+	group := rsqlNode{conjunction: conjunction, children: []rsqlNode{node}}
+	for p.peek() == separator {
+		p.pos++
+
+		node, err = parseFn()
+		if err != nil {
+			return group, err
+		}
+
+		group.children = append(group.children, node)
+	}
+
+	return group, nil
+}
+
+func (p *rsqlParser) parseConstraint() (rsqlNode, error) {
+	if p.peek() != '(' {
+		return p.parseComparison()
+	}
+	p.pos++
+
+	node, err := p.parseOr()
+	if err != nil {
+		return node, err
+	}
+	if p.peek() != ')' {
+		return node, p.error("missing closing parenthesis")
+	}
+	p.pos++
+
+	return node, nil
+}
+
+func (p *rsqlParser) parseComparison() (node rsqlNode, err error) {
+	node.selector = p.readUnreserved()
+	if node.selector == "" {
+		return node, p.error("missing selector")
+	}
+
+	if node.operator, err = p.parseOperator(); err != nil {
+		return node, err
+	}
+
+	node.arguments, err = p.parseArguments()
+
+	return node, err
+}
+
+func (p *rsqlParser) parseOperator() (string, error) {
+	start := p.pos
+
+	switch p.peek() {
+	case '<', '>', '!':
+		p.pos++
+		if p.peek() == '=' {
+			p.pos++
+		} else if p.input[start] == '!' {
+			return "", p.error("invalid operator")
+		}
+	case '=':
+		p.pos++
+		for isAlpha(p.peek()) {
+			p.pos++
+		}
+		if p.peek() != '=' {
+			return "", p.error("invalid operator")
+		}
+		p.pos++
+	default:
+		return "", p.error("missing operator")
+	}
+
+	return p.input[start:p.pos], nil
+}
+
+func (p *rsqlParser) parseArguments() ([]string, error) {
+	if p.peek() != '(' {
+		value, err := p.parseValue()
+		return []string{value}, err
+	}
+	p.pos++
+
+	values := make([]string, 0)
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return values, err
+		}
+		values = append(values, value)
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return values, nil
+		default:
+			return values, p.error("missing closing parenthesis")
+		}
+	}
+}
+
+func (p *rsqlParser) parseValue() (string, error) {
+	quote := p.peek()
+	if quote != '"' && quote != '\'' {
+		value := p.readUnreserved()
+		if value == "" {
+			return "", p.error("missing value")
+		}
+
+		return value, nil
+	}
+	p.pos++
+
+	var value strings.Builder
+	for !p.eof() {
+		c := p.input[p.pos]
+		p.pos++
+
+		switch {
+		case c == '\\' && !p.eof():
+			value.WriteByte(p.input[p.pos])
+			p.pos++
+		case c == quote:
+			return value.String(), nil
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return "", p.error("unterminated quoted value")
+}
+
+func (p *rsqlParser) readUnreserved() string {
+	start := p.pos
+	for !p.eof() && !isReserved(p.input[p.pos]) {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+func isReserved(c byte) bool {
+	return strings.IndexByte("\"'();,=!~<> \t", c) >= 0
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,107 @@
+package query_builder
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRSQL(t *testing.T) {
+	node, err := parseRSQL(`foo==a;(bar=gt=3,bar=in=("x,y",'z'))`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, rsqlNode{
+		conjunction: db.AND,
+		children: []rsqlNode{
+			{selector: "foo", operator: "==", arguments: []string{"a"}},
+			{
+				conjunction: db.OR,
+				children: []rsqlNode{
+					{selector: "bar", operator: "=gt=", arguments: []string{"3"}},
+					{selector: "bar", operator: "=in=", arguments: []string{"x,y", "z"}},
+				},
+			},
+		},
+	}, node)
+}
+
+func TestParseRSQL_Invalid(t *testing.T) {
+	testCases := []struct {
+		expression string
+		err        error
+	}{
+		{"foo", NewInvalidFilterExpressionErr("foo", 3, "missing operator")},
+		{"foo=a", NewInvalidFilterExpressionErr("foo=a", 5, "invalid operator")},
+		{"foo==", NewInvalidFilterExpressionErr("foo==", 5, "missing value")},
+		{"(foo==a", NewInvalidFilterExpressionErr("(foo==a", 7, "missing closing parenthesis")},
+		{"foo==a)", NewInvalidFilterExpressionErr("foo==a)", 6, "unexpected character")},
+		{"foo=='a", NewInvalidFilterExpressionErr("foo=='a", 7, "unterminated quoted value")},
+		{"==a", NewInvalidFilterExpressionErr("==a", 0, "missing selector")},
+	}
+
+	for _, testCase := range testCases {
+		_, err := parseRSQL(testCase.expression)
+		assert.Equal(t, testCase.err, err, testCase.expression)
+	}
+}
+
+func TestBuildFilterExpressions(t *testing.T) {
+	builder := NewQueryBuilder(&[]MockEntity{})
+	fields := builder.filterFields()
+
+	groups, err := builder.buildFilterExpressions(url.Values{"_filter": {"foo!=a,bar<=3"}}, fields)
+	assert.Nil(t, err)
+	assert.Equal(t, []db.FilterGroup{{
+		Conjunction: db.OR,
+		Filters: []db.Filter{
+			{FieldName: "Foo", Operator: "<>", Value: []string{"a"}},
+			{FieldName: "Bar", Operator: "<=", Value: []string{"3"}},
+		},
+		Groups: []db.FilterGroup{},
+	}}, groups)
+
+	// single comparison is wrapped in a group
+	groups, err = builder.buildFilterExpressions(url.Values{"_filter": {"foo=out=(a,b)"}}, fields)
+	assert.Nil(t, err)
+	assert.Equal(t, []db.FilterGroup{{
+		Conjunction: db.AND,
+		Filters:     []db.Filter{{FieldName: "Foo", Operator: "NOT IN", Value: []string{"a", "b"}}},
+		Groups:      []db.FilterGroup{},
+	}}, groups)
+
+	// quoted arguments are not split by comma again
+	groups, err = builder.buildFilterExpressions(url.Values{"_filter": {`foo=in=("a,b",'c');bar=in="x,y"`}}, fields)
+	assert.Nil(t, err)
+	assert.Equal(t, []db.FilterGroup{{
+		Conjunction: db.AND,
+		Filters: []db.Filter{
+			{FieldName: "Foo", Operator: "IN", Value: []string{"a,b", "c"}},
+			{FieldName: "Bar", Operator: "IN", Value: []string{"x,y"}},
+		},
+		Groups: []db.FilterGroup{},
+	}}, groups)
+
+	// unencoded ';' of the raw query is kept by ParseQuery
+	params, err := ParseQuery("_filter=foo==a;bar=gt=3")
+	assert.Nil(t, err)
+	groups, err = builder.buildFilterExpressions(params, fields)
+	assert.Nil(t, err)
+	assert.Equal(t, []db.FilterGroup{{
+		Conjunction: db.AND,
+		Filters: []db.Filter{
+			{FieldName: "Foo", Operator: "=", Value: []string{"a"}},
+			{FieldName: "Bar", Operator: ">", Value: []string{"3"}},
+		},
+		Groups: []db.FilterGroup{},
+	}}, groups)
+
+	// fields not accepted for filtering
+	_, err = builder.buildFilterExpressions(url.Values{"_filter": {"baz==a"}}, fields)
+	assert.Equal(t, InvalidFilterErr{db.Filter{FieldName: "baz", Operator: "="}}, err)
+
+	// unknown operator
+	_, err = builder.buildFilterExpressions(url.Values{"_filter": {"foo=foo=a"}}, fields)
+	assert.Equal(t, InvalidFilterErr{}, err)
+}
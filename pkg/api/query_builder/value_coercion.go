@@ -0,0 +1,168 @@
+package query_builder
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mangalores/go-api-skeleton/pkg/utils"
+)
+
+// timeLayouts accepted formats for time.Time fields, tried in order
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	time.DateOnly,
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Enum implemented by enum types of model fields to restrict accepted filter values
+type Enum interface {
+	EnumValues() []string
+}
+
+type valueTypeErr struct {
+	expected reflect.Type
+}
+
+func (e valueTypeErr) Error() string {
+	return fmt.Sprintf("value must be of type %s", e.expected)
+}
+
+func (e valueTypeErr) paramErr(name string) InvalidParamValueErr {
+	return NewInvalidTypedParamValueErr(name, e.expected.String())
+}
+
+// coerceValues parses string values into a slice of type t, e.g. int: {"1", "2"} => []int{1, 2}
+// values are passed as is if t is unknown or not coercible, e.g. sql.NullString or json columns
+func coerceValues(t reflect.Type, values []string) (interface{}, error) {
+	if t == nil {
+		return values, nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if !coercible(t) {
+		return values, nil
+	}
+
+	coerced := reflect.MakeSlice(reflect.SliceOf(t), 0, len(values))
+	for _, value := range values {
+		v, err := coerceValue(t, value)
+		if err != nil {
+			return values, valueTypeErr{t}
+		}
+
+		coerced = reflect.Append(coerced, v)
+	}
+
+	return coerced.Interface(), nil
+}
+
+func coerceValue(t reflect.Type, value string) (reflect.Value, error) {
+	if t == timeType {
+		return parseTime(value)
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		v := reflect.New(t)
+		err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+
+		return v.Elem(), err
+	}
+
+	v := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.String:
+		if enum, ok := v.Interface().(Enum); ok && !utils.Contains(enum.EnumValues(), value) {
+			return v, fmt.Errorf("%s is not a valid value", value)
+		}
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return v, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return v, err
+		}
+		v.SetFloat(f)
+	default:
+		return v, fmt.Errorf("%s is not coercible", t)
+	}
+
+	return v, nil
+}
+
+// coercible types are parsed by coerceValue, other types are compared as string
+func coercible(t reflect.Type) bool {
+	if t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func parseTime(value string) (v reflect.Value, err error) {
+	var parsed time.Time
+	for _, layout := range timeLayouts {
+		if parsed, err = time.Parse(layout, value); err == nil {
+			return reflect.ValueOf(parsed), nil
+		}
+	}
+
+	return v, err
+}
+
+// fieldType type of the named field of the model struct, nil if not found,
+// field names may be paths over associations, e.g. Author.Name
+func fieldType(model interface{}, fieldName string) (t reflect.Type) {
+	t = getType(model)
+
+	for _, name := range strings.Split(fieldName, pathSeparator) {
+		t = traverseType(t)
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return nil
+		}
+
+		t = field.Type
+	}
+
+	return t
+}
@@ -0,0 +1,112 @@
+package query_builder
+
+import (
+	"database/sql"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStatus string
+
+func (s mockStatus) EnumValues() []string {
+	return []string{"active", "inactive"}
+}
+
+type mockID [2]byte
+
+func (id *mockID) UnmarshalText(text []byte) error {
+	if len(text) != 2 {
+		return assert.AnError
+	}
+	copy(id[:], text)
+
+	return nil
+}
+
+type TypedMockEntity struct {
+	Name      string         `json:"name" api:"filter"`
+	Age       int            `json:"age" api:"filter"`
+	Score     *float64       `json:"score" api:"filter"`
+	Active    bool           `json:"active" api:"filter"`
+	Status    mockStatus     `json:"status" api:"filter"`
+	Ref       mockID         `json:"ref" api:"filter"`
+	Note      sql.NullString `json:"note" api:"filter"`
+	Data      mockJSON       `json:"data" api:"filter"`
+	CreatedAt time.Time      `json:"createdAt" api:"filter"`
+}
+
+// mockJSON json column type like datatypes.JSON
+type mockJSON []byte
+
+func TestCoerceValues(t *testing.T) {
+	entityType := reflect.TypeOf(TypedMockEntity{})
+	typeOf := func(name string) reflect.Type {
+		field, _ := entityType.FieldByName(name)
+		return field.Type
+	}
+
+	testCases := []struct {
+		fieldName string
+		values    []string
+		expected  interface{}
+		ok        bool
+	}{
+		{"Name", []string{"foo"}, []string{"foo"}, true},
+		{"Age", []string{"1", "2"}, []int{1, 2}, true},
+		{"Age", []string{"abc"}, nil, false},
+		{"Score", []string{"1.5"}, []float64{1.5}, true},
+		{"Active", []string{"true"}, []bool{true}, true},
+		{"Active", []string{"yes"}, nil, false},
+		{"Status", []string{"active"}, []mockStatus{"active"}, true},
+		{"Status", []string{"deleted"}, nil, false},
+		{"Ref", []string{"ab"}, []mockID{{'a', 'b'}}, true},
+		{"Ref", []string{"abc"}, nil, false},
+		{"CreatedAt", []string{"2024-02-01"}, []time.Time{time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}, true},
+		{"CreatedAt", []string{"2024-02-01T10:00:00Z"}, []time.Time{time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)}, true},
+		{"CreatedAt", []string{"yesterday"}, nil, false},
+		{"Note", []string{"foo"}, []string{"foo"}, true},
+		{"Data", []string{`{"a":1}`}, []string{`{"a":1}`}, true},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := coerceValues(typeOf(testCase.fieldName), testCase.values)
+		if !testCase.ok {
+			assert.Error(t, err, testCase.fieldName)
+			continue
+		}
+
+		assert.Nil(t, err, testCase.fieldName)
+		assert.Equal(t, testCase.expected, actual, testCase.fieldName)
+	}
+}
+
+func TestBuildFilters_CoercedValues(t *testing.T) {
+	builder := NewQueryBuilder(&[]TypedMockEntity{})
+	fields := builder.filterFields()
+
+	filters, err := builder.buildFilters(url.Values{"age:in": {"1,2"}}, fields)
+	assert.Nil(t, err)
+	assert.Equal(t, []db.Filter{{FieldName: "Age", Operator: "IN", Value: []int{1, 2}}}, filters)
+
+	// pattern operators keep their string values
+	filters, err = builder.buildFilters(url.Values{"status:starts": {"act"}}, fields)
+	assert.Nil(t, err)
+	assert.Equal(t, []db.Filter{{FieldName: "Status", Operator: "LIKE", Value: []string{"act%"}}}, filters)
+
+	// types not coercible are compared as string
+	filters, err = builder.buildFilters(url.Values{"note": {"foo"}, "data": {"bar"}}, fields)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []db.Filter{
+		{FieldName: "Note", Operator: "=", Value: []string{"foo"}},
+		{FieldName: "Data", Operator: "=", Value: []string{"bar"}},
+	}, filters)
+
+	_, err = builder.buildFilters(url.Values{"age:gt": {"abc"}}, fields)
+	assert.Equal(t, NewInvalidTypedParamValueErr("age", "int"), err)
+	assert.Equal(t, "param age has invalid value must be of type int", err.Error())
+}
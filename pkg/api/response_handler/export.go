@@ -0,0 +1,277 @@
+package response_handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/mangalores/go-api-skeleton/pkg/utils"
+)
+
+const (
+	MIMETextCSV           = "text/csv"
+	MIMEApplicationNDJSON = "application/x-ndjson"
+)
+
+// Exporter streams mapped collection items row by row in its media type
+type Exporter interface {
+	MediaType() string
+	// NewWriter columns are the json keys selected by _fields, empty to take the keys of the mapping
+	NewWriter(w io.Writer, columns []string) ExportWriter
+}
+
+// ExportWriter Write takes a single mapped item as json object
+type ExportWriter interface {
+	Write(item json.RawMessage) error
+	Flush() error
+}
+
+func (r *ResponseHandler) registerDefaultExporters() {
+	r.RegisterExporter(CSVExporter{})
+	r.RegisterExporter(NDJSONExporter{})
+}
+
+// RegisterExporter adds or replaces the exporter of its media type
+func (r *ResponseHandler) RegisterExporter(e Exporter) {
+	r.exporters[e.MediaType()] = e
+}
+
+// NegotiateExport exporter of the Accept header, exports have to be accepted explicitly and not by wildcards
+// text/csv, application/json;q=0.5 => CSVExporter
+func (r *ResponseHandler) NegotiateExport(accept string) (Exporter, bool) {
+	for _, mediaRange := range parseAccept(accept) {
+		if e, ok := r.exporters[mediaRange.mediaType]; ok {
+			return e, true
+		}
+		if r.match(mediaRange.mediaType) != nil {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+// ExportColumns json keys of the fields selected by the query in order of selection,
+// nil if the first mapped row lacks any of them, e.g. for renaming mappings, writers use the keys of the rows then
+func (r *ResponseHandler) ExportColumns(query db.QueryObject, first json.RawMessage) []string {
+	keys := selectedKeys(query)
+	if len(keys) == 0 || first == nil {
+		return keys
+	}
+
+	columns, err := objectKeys(first)
+	if err != nil {
+		return nil
+	}
+	for _, key := range keys {
+		if !utils.Contains(columns, key) {
+			return nil
+		}
+	}
+
+	return keys
+}
+
+// ExportRow maps a row of a streamed query and writes it, row is the iterator value
+func (r *ResponseHandler) ExportRow(w ExportWriter, query db.QueryObject, row interface{}) error {
+	item, err := r.MapRow(query, row)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(item)
+}
+
+// MapRow maps a row of a streamed query to json, row is the iterator value
+// rows are mapped by the mapping of their type, or by the mapping of a slice of it if only collections are registered
+func (r *ResponseHandler) MapRow(query db.QueryObject, row interface{}) (json.RawMessage, error) {
+	mapped, err := r.mapRow(utils.StripPointer(row))
+	if err != nil {
+		return nil, err
+	}
+
+	mapped, err = selectFields(query, mapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mapped)
+}
+
+func (r *ResponseHandler) mapRow(row interface{}) (interface{}, error) {
+	if mapFunc, err := r.getMap(row); err == nil {
+		return mapFunc(row)
+	}
+
+	rows := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(row)), 1, 1)
+	rows.Index(0).Set(reflect.ValueOf(row))
+	mapFunc, err := r.getMap(rows.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("mapping not found for %T", row)
+	}
+
+	mapped, err := mapFunc(rows.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	items := reflect.ValueOf(utils.StripPointer(mapped))
+	if (items.Kind() != reflect.Slice && items.Kind() != reflect.Array) || items.Len() != 1 {
+		return nil, fmt.Errorf("mapping of %T must return one item per row", rows.Interface())
+	}
+
+	return items.Index(0).Interface(), nil
+}
+
+// CSVExporter one row per item with a header row of the columns, nested values are json encoded
+type CSVExporter struct{}
+
+func (CSVExporter) MediaType() string {
+	return MIMETextCSV
+}
+
+func (CSVExporter) NewWriter(w io.Writer, columns []string) ExportWriter {
+	return &csvWriter{w: csv.NewWriter(w), columns: columns}
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+	header  bool
+}
+
+func (c *csvWriter) Write(item json.RawMessage) error {
+	if len(c.columns) == 0 {
+		columns, err := objectKeys(item)
+		if err != nil {
+			return err
+		}
+		c.columns = columns
+	}
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	if err := unmarshal(item, &values); err != nil {
+		return err
+	}
+
+	record := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		value, err := csvValue(values[column])
+		if err != nil {
+			return err
+		}
+		record[i] = value
+	}
+
+	return c.w.Write(record)
+}
+
+// Flush writes the header row for exports without items as well
+func (c *csvWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.header || len(c.columns) == 0 {
+		return nil
+	}
+	c.header = true
+
+	return c.w.Write(c.columns)
+}
+
+// csvFormulaPrefixes leading characters spreadsheets evaluate cells by
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvValue strings starting like a formula are prefixed by ' to be shown as text by spreadsheets, e.g. "=1+1" => "'=1+1"
+func csvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		if v != "" && strings.ContainsRune(csvFormulaPrefixes, rune(v[0])) {
+			return "'" + v, nil
+		}
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+}
+
+// objectKeys keys of a json object in order, reserved keys like _links are skipped
+func objectKeys(item json.RawMessage) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(item))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		if !strings.HasPrefix(key, reservedKeyPrefix) {
+			keys = append(keys, key)
+		}
+
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// NDJSONExporter one json object per line
+type NDJSONExporter struct{}
+
+func (NDJSONExporter) MediaType() string {
+	return MIMEApplicationNDJSON
+}
+
+func (NDJSONExporter) NewWriter(w io.Writer, columns []string) ExportWriter {
+	return &ndjsonWriter{w: w}
+}
+
+type ndjsonWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (n *ndjsonWriter) Write(item json.RawMessage) error {
+	n.buf.Reset()
+	if err := json.Compact(&n.buf, item); err != nil {
+		return err
+	}
+	n.buf.WriteByte('\n')
+
+	_, err := n.w.Write(n.buf.Bytes())
+
+	return err
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return nil
+}
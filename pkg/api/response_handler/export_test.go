@@ -0,0 +1,123 @@
+package response_handler
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseHandler_NegotiateExport(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"text/csv", MIMETextCSV},
+		{"application/x-ndjson", MIMEApplicationNDJSON},
+		{"application/json;q=0.5, text/csv", MIMETextCSV},
+		{"text/csv;q=0.5, application/json", ""},
+		{"*/*", ""},
+		{"text/*", ""},
+		{"", ""},
+	}
+
+	r := NewResponseHandler()
+	for _, test := range tests {
+		e, ok := r.NegotiateExport(test.accept)
+		assert.Equal(t, test.expected != "", ok, test.accept)
+		if ok {
+			assert.Equal(t, test.expected, e.MediaType(), test.accept)
+		}
+	}
+}
+
+func TestResponseHandler_ExportRow(t *testing.T) {
+	type Thing struct {
+		ID    int               `json:"id"`
+		Title string            `json:"title"`
+		Tags  []string          `json:"tags"`
+		Links map[string]string `json:"_links"`
+	}
+	r := NewResponseHandler()
+	r.Register(Thing{}, func(e interface{}) (interface{}, error) { return e, nil })
+
+	query := db.NewCollectionQuery(&[]Thing{})
+	rows := []Thing{
+		{ID: 1, Title: "foo, bar", Tags: []string{"a"}, Links: map[string]string{"self": "/things/1"}},
+		{ID: 2, Title: "baz"},
+	}
+
+	tests := []struct {
+		exporter Exporter
+		columns  []string
+		expected string
+	}{
+		{CSVExporter{}, nil, "id,title,tags\n1,\"foo, bar\",\"[\"\"a\"\"]\"\n2,baz,\n"},
+		{CSVExporter{}, []string{"title"}, "title\n\"foo, bar\"\nbaz\n"},
+		{NDJSONExporter{}, nil, "{\"id\":1,\"title\":\"foo, bar\",\"tags\":[\"a\"],\"_links\":{\"self\":\"/things/1\"}}\n{\"id\":2,\"title\":\"baz\",\"tags\":null,\"_links\":null}\n"},
+	}
+
+	for _, test := range tests {
+		out := bytes.Buffer{}
+		w := test.exporter.NewWriter(&out, test.columns)
+
+		it := db.NewSliceIterator(rows)
+		for it.Next() {
+			assert.NoError(t, r.ExportRow(w, query, it.Value()))
+		}
+		assert.NoError(t, w.Flush())
+		assert.Equal(t, test.expected, out.String(), test.exporter.MediaType())
+	}
+}
+
+func TestResponseHandler_MapRow(t *testing.T) {
+	type Thing struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	type Label struct {
+		Label string `json:"label"`
+	}
+
+	// rows of resources registering the collection mapping only are mapped by it
+	r := NewResponseHandler()
+	r.Register([]Thing{}, func(e interface{}) (interface{}, error) {
+		labels := make([]Label, 0)
+		for _, thing := range e.([]Thing) {
+			labels = append(labels, Label{thing.Title})
+		}
+		return labels, nil
+	})
+
+	query := db.NewCollectionQuery(&[]Thing{})
+	row, err := r.MapRow(query, &Thing{ID: 1, Title: "foo"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"label":"foo"}`, string(row))
+
+	// selected keys renamed by the mapping leave the columns to the rows
+	query.SetFields([]string{"Title"})
+	assert.Nil(t, r.ExportColumns(query, row))
+	assert.Equal(t, []string{"title"}, r.ExportColumns(query, nil))
+	assert.Equal(t, []string{"title"}, r.ExportColumns(query, []byte(`{"title":"foo"}`)))
+
+	_, err = NewResponseHandler().MapRow(query, &Thing{})
+	assert.Error(t, err)
+}
+
+func TestCSVExporter_FormulaValues(t *testing.T) {
+	out := bytes.Buffer{}
+	w := CSVExporter{}.NewWriter(&out, []string{"a", "b", "c", "d", "e", "f"})
+
+	assert.NoError(t, w.Write([]byte(`{"a":"=SUM(A1:A2)","b":"+1","c":"-1","d":"@cmd","e":-1,"f":"a=b"}`)))
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, "a,b,c,d,e,f\n'=SUM(A1:A2),'+1,'-1,'@cmd,-1,a=b\n", out.String())
+}
+
+func TestCSVExporter_Empty(t *testing.T) {
+	out := bytes.Buffer{}
+	w := CSVExporter{}.NewWriter(&out, []string{"id", "title"})
+
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, "id,title\n", out.String())
+}
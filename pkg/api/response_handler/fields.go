@@ -0,0 +1,118 @@
+package response_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/mangalores/go-api-skeleton/pkg/utils"
+)
+
+const reservedKeyPrefix = "_"
+
+// selectFields removes the keys of model fields not selected in the query from mapped items,
+// keys added or renamed by the mapping are kept as they are not known to the model
+func selectFields(query db.QueryObject, mapped interface{}) (interface{}, error) {
+	keys := deselectedKeys(query)
+	if len(keys) == 0 || mapped == nil {
+		return mapped, nil
+	}
+
+	data, err := json.Marshal(mapped)
+	if err != nil {
+		return nil, err
+	}
+
+	if v := reflect.ValueOf(utils.StripPointer(mapped)); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		items := make([]map[string]interface{}, 0)
+		if err = unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			filterKeys(item, keys)
+		}
+
+		return items, nil
+	}
+
+	item := make(map[string]interface{})
+	if err = unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+
+	return filterKeys(item, keys), nil
+}
+
+// unmarshal keeps numbers as json.Number to not lose precision of large integers
+func unmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(v)
+}
+
+func filterKeys(item map[string]interface{}, keys []string) map[string]interface{} {
+	for _, key := range keys {
+		delete(item, key)
+	}
+
+	return item
+}
+
+// selectedKeys json names of the selected model fields
+func selectedKeys(query db.QueryObject) []string {
+	keys := make([]string, 0)
+	if len(query.Fields()) == 0 {
+		return keys
+	}
+
+	t := modelType(query.Model())
+	if t == nil || t.Kind() != reflect.Struct {
+		return keys
+	}
+
+	for _, name := range query.Fields() {
+		field, ok := t.FieldByName(name)
+		if !ok {
+			continue
+		}
+		if key, ok := utils.JSONName(field); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// deselectedKeys json names of the model fields not selected, empty if the query selects all fields
+func deselectedKeys(query db.QueryObject) []string {
+	keys := make([]string, 0)
+	if len(query.Fields()) == 0 {
+		return keys
+	}
+
+	t := modelType(query.Model())
+	if t == nil || t.Kind() != reflect.Struct {
+		return keys
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		key, ok := utils.JSONName(field)
+		if ok && !strings.HasPrefix(key, reservedKeyPrefix) && !utils.Contains(query.Fields(), field.Name) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func modelType(model interface{}) reflect.Type {
+	t := reflect.TypeOf(model)
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,75 @@
+package response_handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockEntity struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,omitempty"`
+	Age  int    `json:"age"`
+}
+
+type MockItem struct {
+	MockEntity
+	Links Links `json:"_links"`
+}
+
+func TestSelectFields(t *testing.T) {
+	query := db.NewQuery(&MockEntity{})
+	query.SetFields([]string{"ID", "Name"})
+
+	actual, err := selectFields(query, MockItem{MockEntity: MockEntity{ID: 9007199254740993, Name: "foo", Age: 3}, Links: Links{}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":     json.Number("9007199254740993"),
+		"name":   "foo",
+		"_links": map[string]interface{}{},
+	}, actual)
+
+	actual, err = selectFields(query, []MockEntity{{ID: 1, Name: "foo", Age: 3}, {ID: 2, Age: 4}})
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"id": json.Number("1"), "name": "foo"},
+		{"id": json.Number("2")},
+	}, actual)
+}
+
+func TestSelectFields_NoSelection(t *testing.T) {
+	query := db.NewQuery(&MockEntity{})
+	item := MockEntity{ID: 1}
+
+	actual, err := selectFields(query, item)
+	assert.Nil(t, err)
+	assert.Equal(t, item, actual)
+}
+
+func TestSelectFields_RenamingMapping(t *testing.T) {
+	type renamed struct {
+		ID    int64  `json:"id"`
+		Label string `json:"label"`
+		Age   int    `json:"age"`
+		Kind  string `json:"kind"`
+	}
+	r := NewResponseHandler()
+	r.Register(MockEntity{}, func(e interface{}) (interface{}, error) {
+		entity := e.(MockEntity)
+		return renamed{ID: entity.ID, Label: entity.Name, Age: entity.Age, Kind: "thing"}, nil
+	})
+
+	query := db.NewQuery(&MockEntity{})
+	query.SetFields([]string{"ID", "Name"})
+	query.SetResult(&MockEntity{ID: 1, Name: "foo"})
+
+	res, err := r.Map("/things", query)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":    json.Number("1"),
+		"label": "foo",
+		"kind":  "thing",
+	}, res.Item)
+}
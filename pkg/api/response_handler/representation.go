@@ -0,0 +1,312 @@
+package response_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// MIMEJSONArray plain json array of the items, pagination is in the Link and X-Total-Count headers
+	MIMEJSONArray       = "application/vnd.array+json"
+	MIMEHALJSON         = "application/hal+json"
+	MIMEJSONAPI         = "application/vnd.api+json"
+	HeaderTotalCount    = "X-Total-Count"
+	HeaderTotalRelation = "X-Total-Relation"
+)
+
+// link relations in the order of the Link header
+var linkRelations = []string{"self", "first", "prev", "next", "last"}
+
+// Representation renders mapped query results in its media type
+type Representation interface {
+	MediaType() string
+	Render(ctx echo.Context, status int, res *Response) error
+}
+
+func (r *ResponseHandler) registerDefaultRepresentations() {
+	r.RegisterRepresentation(JSONRepresentation{})
+	r.RegisterRepresentation(ArrayRepresentation{})
+	r.RegisterRepresentation(HALRepresentation{})
+	r.RegisterRepresentation(JSONAPIRepresentation{})
+}
+
+// RegisterRepresentation adds or replaces the representation of its media type
+func (r *ResponseHandler) RegisterRepresentation(rep Representation) {
+	for i, registered := range r.representations {
+		if registered.MediaType() == rep.MediaType() {
+			r.representations[i] = rep
+			return
+		}
+	}
+
+	r.representations = append(r.representations, rep)
+}
+
+// SetDefaultRepresentation media type responded if the request accepts any, application/json by default
+func (r *ResponseHandler) SetDefaultRepresentation(mediaType string) {
+	r.defaultType = mediaType
+}
+
+// Negotiate representation of the Accept header, the most specific of equal quality wins
+// requests without Accept header get the default, not acceptable requests a 406 error
+// text/html;q=0.9, application/hal+json => HALRepresentation
+func (r *ResponseHandler) Negotiate(accept string) (Representation, error) {
+	if strings.TrimSpace(accept) == "" {
+		return r.defaultRepresentation(), nil
+	}
+
+	for _, mediaRange := range parseAccept(accept) {
+		if rep := r.match(mediaRange.mediaType); rep != nil {
+			return rep, nil
+		}
+	}
+
+	return nil, echo.NewHTTPError(http.StatusNotAcceptable, fmt.Sprintf("none of %s is available", accept))
+}
+
+func (r *ResponseHandler) match(mediaType string) Representation {
+	if mediaType == "*/*" {
+		return r.defaultRepresentation()
+	}
+
+	prefix, wildcard := strings.CutSuffix(mediaType, "*")
+	if wildcard && strings.HasPrefix(r.defaultType, prefix) {
+		return r.defaultRepresentation()
+	}
+
+	for _, rep := range r.representations {
+		if rep.MediaType() == mediaType || (wildcard && strings.HasPrefix(rep.MediaType(), prefix)) {
+			return rep
+		}
+	}
+
+	return nil
+}
+
+func (r *ResponseHandler) defaultRepresentation() Representation {
+	for _, rep := range r.representations {
+		if rep.MediaType() == r.defaultType {
+			return rep
+		}
+	}
+
+	return r.representations[0]
+}
+
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept media ranges ordered by quality and specificity, ranges of quality 0 are dropped
+func parseAccept(accept string) []mediaRange {
+	ranges := make([]mediaRange, 0)
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mr := mediaRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), quality: 1}
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if name != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				mr.quality = q
+			}
+		}
+		if mr.mediaType != "" && mr.quality > 0 {
+			ranges = append(ranges, mr)
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].quality != ranges[j].quality {
+			return ranges[i].quality > ranges[j].quality
+		}
+		return specificity(ranges[i].mediaType) > specificity(ranges[j].mediaType)
+	})
+
+	return ranges
+}
+
+func specificity(mediaType string) int {
+	switch {
+	case mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// JSONRepresentation default format, collections have _links, _metadata and the items in _embedded
+type JSONRepresentation struct{}
+
+func (JSONRepresentation) MediaType() string {
+	return echo.MIMEApplicationJSON
+}
+
+func (JSONRepresentation) Render(ctx echo.Context, status int, res *Response) error {
+	return ctx.JSON(status, res.body())
+}
+
+// ArrayRepresentation collections as plain json array, links and total are set as headers
+type ArrayRepresentation struct{}
+
+func (ArrayRepresentation) MediaType() string {
+	return MIMEJSONArray
+}
+
+func (ArrayRepresentation) Render(ctx echo.Context, status int, res *Response) error {
+	if !res.Collection {
+		return ctx.JSON(status, res.Item)
+	}
+
+	header := ctx.Response().Header()
+	if link := linkHeader(res.Links); link != "" {
+		header.Set("Link", link)
+	}
+	if res.Metadata != nil && res.Metadata.Total != nil {
+		header.Set(HeaderTotalCount, strconv.FormatInt(*res.Metadata.Total, 10))
+		if res.Metadata.TotalRelation != "" {
+			header.Set(HeaderTotalRelation, res.Metadata.TotalRelation)
+		}
+	}
+
+	return ctx.JSON(status, res.Items)
+}
+
+// linkHeader RFC 8288 Link header of the links
+// <https://example.com/things?_limit=5&_offset=5>; rel="next"
+func linkHeader(links Links) string {
+	values := make([]string, 0, len(links))
+	for _, rel := range linkRelations {
+		if link, ok := links[rel]; ok {
+			values = append(values, fmt.Sprintf(`<%s>; rel="%s"`, link.Href, rel))
+		}
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// HALRepresentation collections with the metadata as properties next to _links and _embedded, entities as mapped
+type HALRepresentation struct{}
+
+func (HALRepresentation) MediaType() string {
+	return MIMEHALJSON
+}
+
+func (h HALRepresentation) Render(ctx echo.Context, status int, res *Response) error {
+	if !res.Collection {
+		return renderJSON(ctx, status, h.MediaType(), res.Item)
+	}
+
+	body := make(map[string]interface{})
+	if res.Metadata != nil {
+		if err := toObject(res.Metadata, &body); err != nil {
+			return err
+		}
+	}
+	body["_links"] = res.Links
+	body["_embedded"] = Embedded{"items": res.Items}
+
+	return renderJSON(ctx, status, h.MediaType(), body)
+}
+
+// JSONAPIRepresentation JSON:API documents, the type of resources is the model name
+// the id key of mapped items is the resource id, the other keys are the attributes and reserved keys are dropped
+type JSONAPIRepresentation struct{}
+
+type jsonAPIDocument struct {
+	Data  interface{}       `json:"data"`
+	Links map[string]string `json:"links,omitempty"`
+	Meta  *CollectionOpts   `json:"meta,omitempty"`
+}
+
+type jsonAPIResource struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+func (JSONAPIRepresentation) MediaType() string {
+	return MIMEJSONAPI
+}
+
+func (j JSONAPIRepresentation) Render(ctx echo.Context, status int, res *Response) error {
+	resourceType := ""
+	if t := modelType(res.Model); t != nil && t.Name() != "" {
+		resourceType = strings.ToLower(t.Name()[:1]) + t.Name()[1:]
+	}
+
+	doc := jsonAPIDocument{}
+	if !res.Collection {
+		if res.Item != nil {
+			item := make(map[string]interface{})
+			if err := toObject(res.Item, &item); err != nil {
+				return err
+			}
+			doc.Data = newJSONAPIResource(resourceType, item)
+		}
+
+		return renderJSON(ctx, status, j.MediaType(), doc)
+	}
+
+	items := make([]map[string]interface{}, 0)
+	if res.Items != nil {
+		if err := toObject(res.Items, &items); err != nil {
+			return err
+		}
+	}
+	data := make([]jsonAPIResource, 0, len(items))
+	for _, item := range items {
+		data = append(data, newJSONAPIResource(resourceType, item))
+	}
+	doc.Data = data
+	doc.Meta = res.Metadata
+	if len(res.Links) > 0 {
+		doc.Links = make(map[string]string, len(res.Links))
+		for rel, link := range res.Links {
+			doc.Links[rel] = link.Href
+		}
+	}
+
+	return renderJSON(ctx, status, j.MediaType(), doc)
+}
+
+func newJSONAPIResource(resourceType string, item map[string]interface{}) jsonAPIResource {
+	resource := jsonAPIResource{Type: resourceType, Attributes: make(map[string]interface{}, len(item))}
+	for key, value := range item {
+		switch {
+		case key == "id":
+			resource.ID = fmt.Sprint(value)
+		case !strings.HasPrefix(key, reservedKeyPrefix):
+			resource.Attributes[key] = value
+		}
+	}
+
+	return resource
+}
+
+// toObject converts mapped items to generic json objects
+func toObject(mapped interface{}, v interface{}) error {
+	data, err := json.Marshal(mapped)
+	if err != nil {
+		return err
+	}
+
+	return unmarshal(data, v)
+}
+
+func renderJSON(ctx echo.Context, status int, mediaType string, body interface{}) error {
+	ctx.Response().Header().Set(echo.HeaderContentType, mediaType)
+	ctx.Response().WriteHeader(status)
+
+	return ctx.Echo().JSONSerializer.Serialize(ctx, body, "")
+}
@@ -0,0 +1,129 @@
+package response_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockThing struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func TestResponseHandler_Negotiate(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected string
+	}{
+		{"", echo.MIMEApplicationJSON},
+		{"*/*", echo.MIMEApplicationJSON},
+		{"application/json", echo.MIMEApplicationJSON},
+		{"application/hal+json", MIMEHALJSON},
+		{"text/html;q=0.9, application/vnd.api+json", MIMEJSONAPI},
+		{"application/*;q=0.5, application/vnd.array+json", MIMEJSONArray},
+		{"application/hal+json;q=0.2, application/vnd.api+json;q=0.8", MIMEJSONAPI},
+		{"text/html, */*;q=0.1", echo.MIMEApplicationJSON},
+		{"application/json;q=0, application/hal+json", MIMEHALJSON},
+	}
+
+	r := NewResponseHandler()
+	for _, test := range tests {
+		rep, err := r.Negotiate(test.accept)
+		assert.NoError(t, err, test.accept)
+		assert.Equal(t, test.expected, rep.MediaType(), test.accept)
+	}
+
+	_, err := r.Negotiate("text/html, application/json;q=0")
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotAcceptable, httpErr.Code)
+
+	r.SetDefaultRepresentation(MIMEHALJSON)
+	rep, err := r.Negotiate("*/*")
+	assert.NoError(t, err)
+	assert.Equal(t, MIMEHALJSON, rep.MediaType())
+}
+
+func TestRepresentation_Render(t *testing.T) {
+	total := int64(12)
+	res := &Response{
+		BasePath:   "/things",
+		Model:      &[]MockThing{},
+		Collection: true,
+		Items:      []MockThing{{ID: 1, Title: "foo"}},
+		Links: Links{
+			"self": {"/things?_limit=1&_offset=0"},
+			"next": {"/things?_limit=1&_offset=1"},
+		},
+		Metadata: &CollectionOpts{Limit: 1, Total: &total},
+	}
+
+	tests := []struct {
+		representation Representation
+		contentType    string
+		headers        map[string]string
+		expected       string
+	}{
+		{
+			JSONRepresentation{},
+			echo.MIMEApplicationJSON,
+			nil,
+			`{"_links":{"self":{"href":"/things?_limit=1&_offset=0"},"next":{"href":"/things?_limit=1&_offset=1"}},"_metadata":{"offset":0,"limit":1,"total":12},"_embedded":{"items":[{"id":1,"title":"foo"}]}}`,
+		},
+		{
+			ArrayRepresentation{},
+			echo.MIMEApplicationJSON,
+			map[string]string{
+				"Link":           `</things?_limit=1&_offset=0>; rel="self", </things?_limit=1&_offset=1>; rel="next"`,
+				HeaderTotalCount: "12",
+			},
+			`[{"id":1,"title":"foo"}]`,
+		},
+		{
+			HALRepresentation{},
+			MIMEHALJSON,
+			nil,
+			`{"_links":{"self":{"href":"/things?_limit=1&_offset=0"},"next":{"href":"/things?_limit=1&_offset=1"}},"_embedded":{"items":[{"id":1,"title":"foo"}]},"offset":0,"limit":1,"total":12}`,
+		},
+		{
+			JSONAPIRepresentation{},
+			MIMEJSONAPI,
+			nil,
+			`{"data":[{"type":"mockThing","id":"1","attributes":{"title":"foo"}}],"links":{"self":"/things?_limit=1&_offset=0","next":"/things?_limit=1&_offset=1"},"meta":{"offset":0,"limit":1,"total":12}}`,
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/things", nil), rec)
+
+		assert.NoError(t, test.representation.Render(ctx, http.StatusOK, res))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), test.contentType)
+		for name, value := range test.headers {
+			assert.Equal(t, value, rec.Header().Get(name), name)
+		}
+		assert.JSONEq(t, test.expected, rec.Body.String(), test.representation.MediaType())
+	}
+}
+
+func TestJSONAPIRepresentation_RenderEntity(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/things/1", nil), rec)
+	res := &Response{Model: &MockThing{}, Item: MockThing{ID: 1, Title: "foo"}}
+
+	assert.NoError(t, JSONAPIRepresentation{}.Render(ctx, http.StatusOK, res))
+
+	doc := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, map[string]interface{}{
+		"type":       "mockThing",
+		"id":         "1",
+		"attributes": map[string]interface{}{"title": "foo"},
+	}, doc["data"])
+}
@@ -2,71 +2,106 @@ package response_handler
 
 import (
 	"fmt"
-	"github.com/mangalores/go-api-skeleton/pkg/utils"
+	"net/url"
 	"reflect"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/mangalores/go-api-skeleton/pkg/api/query_builder"
 	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/mangalores/go-api-skeleton/pkg/utils"
 )
 
 type MappingFunc func(e interface{}) (interface{}, error)
 type LinkResolver func(ctx echo.Context, e interface{}) (string, error)
 
+// ResponseHandler maps query results and renders them in the representation negotiated by the Accept header
 type ResponseHandler struct {
-	mappings map[string]MappingFunc
+	mappings        map[string]MappingFunc
+	representations []Representation
+	exporters       map[string]Exporter
+	defaultType     string
 }
 
 func NewResponseHandler() *ResponseHandler {
-	return &ResponseHandler{
-		make(map[string]MappingFunc),
+	r := &ResponseHandler{
+		mappings:    make(map[string]MappingFunc),
+		exporters:   make(map[string]Exporter),
+		defaultType: echo.MIMEApplicationJSON,
 	}
+	r.registerDefaultRepresentations()
+	r.registerDefaultExporters()
+
+	return r
 }
 
 func (r *ResponseHandler) Register(i interface{}, m MappingFunc) {
 	r.mappings[reflect.TypeOf(i).String()] = m
 }
 
+// Handle mapped result of the query in the default json format, errors are set on the query
 func (r *ResponseHandler) Handle(basePath string, query db.QueryObject) interface{} {
-	switch query.(type) {
-	case db.SlicedQueryObject:
-		return r.mapCollection(basePath, query.(db.SlicedQueryObject))
-	default:
-		return r.mapSimpleQuery(query.(db.QueryObject))
+	res, err := r.Map(basePath, query)
+	if err != nil {
+		query.SetError(err)
+		return nil
 	}
 
+	return res.body()
 }
 
-func (r *ResponseHandler) mapSimpleQuery(query db.QueryObject) interface{} {
-	println(fmt.Sprintf("%T", query.Result()))
+// Respond renders the result of the query in the representation accepted by the request
+func (r *ResponseHandler) Respond(ctx echo.Context, status int, basePath string, query db.QueryObject) error {
+	representation, err := r.Negotiate(ctx.Request().Header.Get(echo.HeaderAccept))
+	if err != nil {
+		return err
+	}
+
+	res, err := r.Map(basePath, query)
+	if err != nil {
+		return err
+	}
+
+	return representation.Render(ctx, status, res)
+}
+
+// Map applies the registered mapping and the field selection to the result of the query
+func (r *ResponseHandler) Map(basePath string, query db.QueryObject) (*Response, error) {
 	result := utils.StripPointer(query.Result())
 	mapFunc, err := r.getMap(result)
 	if err != nil {
-		query.SetError(err)
-		return nil
+		return nil, err
 	}
 	if mapFunc == nil {
-		query.SetError(fmt.Errorf("mapping not found for %T", result))
-		return nil
+		return nil, fmt.Errorf("mapping not found for %T", result)
 	}
 
 	mapped, err := mapFunc(result)
 	if err != nil {
-		query.SetError(err)
-		return nil
+		return nil, err
 	}
 
-	return mapped
-}
-
-func (r *ResponseHandler) mapCollection(basePath string, query db.SlicedQueryObject) interface{} {
-	result := utils.StripPointer(query.Result())
-	m, err := r.getMap(result)
+	mapped, err = selectFields(query, mapped)
 	if err != nil {
-		query.SetError(err)
-		return nil
+		return nil, err
 	}
 
-	return NewCollection(basePath, query, m)
+	res := &Response{BasePath: basePath, Model: query.Model()}
+	sliced, ok := query.(db.SlicedQueryObject)
+	if !ok {
+		res.Item = mapped
+		return res, nil
+	}
+
+	res.Collection = true
+	res.Items = mapped
+	if sel := sliced.Slice(); sel != nil {
+		opts := NewCollectionOpts(sel)
+		res.Links = GenerateCollectionLinks(sel, basePath).Links
+		res.Metadata = &opts
+	}
+
+	return res, nil
 }
 
 func (r *ResponseHandler) getMap(i interface{}) (MappingFunc, error) {
@@ -106,58 +141,152 @@ func NewCollection(basePath string, query db.SlicedQueryObject, mapFunc MappingF
 		return nil
 	}
 
-	collection := Collection{
-		Embedded: Embedded{
-			"items": items,
-		},
+	items, err = selectFields(query, items)
+	if err != nil {
+		query.SetError(err)
+		return nil
 	}
 
+	res := &Response{BasePath: basePath, Model: query.Model(), Collection: true, Items: items}
 	if sel := query.Slice(); sel != nil {
-		collection.LinkOpts = GenerateCollectionLinks(sel, basePath)
-		collection.Metadata = CollectionOpts{
-			Offset: sel.Offset,
-			Limit:  sel.Limit,
-			Total:  sel.Total,
-		}
+		opts := NewCollectionOpts(sel)
+		res.Links = GenerateCollectionLinks(sel, basePath).Links
+		res.Metadata = &opts
 	}
 
-	return &collection
+	return res.collection()
 }
 
-func GenerateCollectionLinks(s *db.Slice, path string) LinkOpts {
-	links := make(Links)
+func NewCollectionOpts(s *db.Slice) CollectionOpts {
+	opts := CollectionOpts{
+		Offset:     s.Offset,
+		Limit:      s.Limit,
+		NextCursor: s.NextCursor,
+		PrevCursor: s.PrevCursor,
+	}
+	if s.Keyset || s.Count == db.CountNone {
+		return opts
+	}
 
-	links["self"] = Link{
-		fmt.Sprintf("%s?offset=%d&limit=%d", path, s.Offset, s.Limit),
+	total := s.Total
+	opts.Total = &total
+
+	switch {
+	case s.Count == db.CountCapped && s.Capped:
+		opts.TotalRelation = TotalLowerBound
+	case s.Count == db.CountEstimate:
+		opts.TotalRelation = TotalEstimated
 	}
 
-	firstOffset := 0
-	if firstOffset != s.Offset {
-		links["first"] = Link{
-			fmt.Sprintf("%s?offset=%d&limit=%d", path, firstOffset, s.Limit),
-		}
+	return opts
+}
+
+// GenerateCollectionLinks basePath is the request uri, its query params are kept in the links with the page params replaced
+// /things?name=foo&_offset=10&_limit=5 => next /things?_limit=5&_offset=15&name=foo
+func GenerateCollectionLinks(s *db.Slice, basePath string) LinkOpts {
+	if s.Keyset {
+		return GenerateCursorLinks(s, basePath)
 	}
 
-	lastOffset := s.Total - s.Total%int64(s.Limit)
-	if lastOffset > int64(s.Offset) {
-		links["last"] = Link{
-			fmt.Sprintf("%s?offset=%d&limit=%d", path, lastOffset, s.Limit),
-		}
+	path, params := parseBasePath(basePath)
+	links := make(Links)
+
+	links["self"] = offsetLink(path, params, s.Offset, s.Limit)
+	if s.Limit <= 0 {
+		return LinkOpts{links}
 	}
 
-	prevOffset := s.Offset - s.Limit
-	if prevOffset > 0 {
-		links["prev"] = Link{
-			fmt.Sprintf("%s?offset=%d&limit=%d", path, prevOffset, s.Limit),
+	if s.Offset > 0 {
+		links["first"] = offsetLink(path, params, 0, s.Limit)
+		links["prev"] = offsetLink(path, params, max(s.Offset-s.Limit, 0), s.Limit)
+	}
+
+	// the last page continues the pages from the current offset
+	if s.Counted() && s.Total > int64(s.Offset) {
+		lastOffset := int64(s.Offset) + (s.Total-1-int64(s.Offset))/int64(s.Limit)*int64(s.Limit)
+		if lastOffset > int64(s.Offset) {
+			links["last"] = offsetLink(path, params, int(lastOffset), s.Limit)
 		}
 	}
 
 	nextOffset := s.Offset + s.Limit
-	if s.Total > int64(nextOffset) {
-		links["next"] = Link{
-			fmt.Sprintf("%s?offset=%d&limit=%d", path, nextOffset, s.Limit),
+	if (s.Counted() && s.Total > int64(nextOffset)) || (!s.Counted() && s.HasMore) {
+		links["next"] = offsetLink(path, params, nextOffset, s.Limit)
+	}
+
+	return LinkOpts{links}
+}
+
+// GenerateCursorLinks links of keyset paginated collections, there is no last page without counting
+func GenerateCursorLinks(s *db.Slice, basePath string) LinkOpts {
+	path, params := parseBasePath(basePath)
+	links := make(Links)
+
+	links["self"] = cursorLink(path, params, "", s.Limit)
+	if s.Cursor != nil {
+		if cursor, err := db.EncodeCursor(*s.Cursor); err == nil {
+			links["self"] = cursorLink(path, params, cursor, s.Limit)
 		}
+
+		links["first"] = cursorLink(path, params, "", s.Limit)
+	}
+
+	if s.PrevCursor != "" {
+		links["prev"] = cursorLink(path, params, s.PrevCursor, s.Limit)
+	}
+
+	if s.NextCursor != "" {
+		links["next"] = cursorLink(path, params, s.NextCursor, s.Limit)
 	}
 
 	return LinkOpts{links}
 }
+
+// parseBasePath splits the request uri into path and query params, ';' of filter groups is kept
+func parseBasePath(basePath string) (string, url.Values) {
+	u, err := url.Parse(basePath)
+	if err != nil {
+		return basePath, url.Values{}
+	}
+
+	params, err := query_builder.ParseQuery(u.RawQuery)
+	if err != nil {
+		params = url.Values{}
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String(), params
+}
+
+func offsetLink(path string, params url.Values, offset int, limit int) Link {
+	return pageLink(path, params, map[string]string{
+		query_builder.OffsetField: strconv.Itoa(offset),
+		query_builder.LimitField:  strconv.Itoa(limit),
+	})
+}
+
+func cursorLink(path string, params url.Values, cursor string, limit int) Link {
+	return pageLink(path, params, map[string]string{
+		query_builder.CursorField: cursor,
+		query_builder.LimitField:  strconv.Itoa(limit),
+	})
+}
+
+// pageLink replaces the page params, empty values remove the param as does _offset for cursors and vice versa
+func pageLink(path string, params url.Values, page map[string]string) Link {
+	query := make(url.Values, len(params)+len(page))
+	for name, values := range params {
+		query[name] = values
+	}
+	delete(query, query_builder.OffsetField)
+	delete(query, query_builder.CursorField)
+
+	for name, value := range page {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	return Link{path + "?" + query.Encode()}
+}
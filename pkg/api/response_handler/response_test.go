@@ -0,0 +1,75 @@
+package response_handler
+
+import (
+	"testing"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCollectionLinks(t *testing.T) {
+	tests := []struct {
+		slice    db.Slice
+		basePath string
+		expected Links
+	}{
+		{
+			db.Slice{Offset: 0, Limit: 5, Total: 10},
+			"/things",
+			Links{
+				"self": {"/things?_limit=5&_offset=0"},
+				"last": {"/things?_limit=5&_offset=5"},
+				"next": {"/things?_limit=5&_offset=5"},
+			},
+		},
+		{
+			db.Slice{Offset: 3, Limit: 5, Total: 14},
+			"/things?name=foo&_sort=name:desc&_offset=3&_limit=5&_embed=owner",
+			Links{
+				"self":  {"/things?_embed=owner&_limit=5&_offset=3&_sort=name%3Adesc&name=foo"},
+				"first": {"/things?_embed=owner&_limit=5&_offset=0&_sort=name%3Adesc&name=foo"},
+				"prev":  {"/things?_embed=owner&_limit=5&_offset=0&_sort=name%3Adesc&name=foo"},
+				"last":  {"/things?_embed=owner&_limit=5&_offset=13&_sort=name%3Adesc&name=foo"},
+				"next":  {"/things?_embed=owner&_limit=5&_offset=8&_sort=name%3Adesc&name=foo"},
+			},
+		},
+		{
+			db.Slice{Offset: 5, Limit: 5, Total: 10},
+			"/things?_offset=5&_limit=5",
+			Links{
+				"self":  {"/things?_limit=5&_offset=5"},
+				"first": {"/things?_limit=5&_offset=0"},
+				"prev":  {"/things?_limit=5&_offset=0"},
+			},
+		},
+		{
+			db.Slice{Offset: 0, Limit: 5, Count: db.CountNone, HasMore: true},
+			"/things?_count=false",
+			Links{
+				"self": {"/things?_count=false&_limit=5&_offset=0"},
+				"next": {"/things?_count=false&_limit=5&_offset=5"},
+			},
+		},
+		{
+			db.Slice{Limit: 5, Keyset: true, NextCursor: "abc"},
+			"/things?_offset=10&status=a&status=b",
+			Links{
+				"self": {"/things?_limit=5&status=a&status=b"},
+				"next": {"/things?_cursor=abc&_limit=5&status=a&status=b"},
+			},
+		},
+		{
+			db.Slice{Offset: 0, Limit: 5, Total: 10},
+			"/things?_or=name=foo;age:gt=3&_filter=a==1;b==2&_limit=5",
+			Links{
+				"self": {"/things?_filter=a%3D%3D1%3Bb%3D%3D2&_limit=5&_offset=0&_or=name%3Dfoo%3Bage%3Agt%3D3"},
+				"last": {"/things?_filter=a%3D%3D1%3Bb%3D%3D2&_limit=5&_offset=5&_or=name%3Dfoo%3Bage%3Agt%3D3"},
+				"next": {"/things?_filter=a%3D%3D1%3Bb%3D%3D2&_limit=5&_offset=5&_or=name%3Dfoo%3Bage%3Agt%3D3"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, GenerateCollectionLinks(&test.slice, test.basePath).Links, test.basePath)
+	}
+}
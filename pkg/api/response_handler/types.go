@@ -14,12 +14,56 @@ type Collection struct {
 	Embedded Embedded       `json:"_embedded"`
 }
 
+const (
+	TotalLowerBound = "gte"
+	TotalEstimated  = "estimated"
+)
+
+// CollectionOpts Total is omitted if not counted, TotalRelation is set if Total is not exact
 type CollectionOpts struct {
-	Offset int   `json:"offset"`
-	Limit  int   `json:"limit"`
-	Total  int64 `json:"total"`
+	Offset        int    `json:"offset"`
+	Limit         int    `json:"limit"`
+	Total         *int64 `json:"total,omitempty"`
+	TotalRelation string `json:"totalRelation,omitempty"`
+	NextCursor    string `json:"nextCursor,omitempty"`
+	PrevCursor    string `json:"prevCursor,omitempty"`
 }
 
 type Link struct {
 	Href string `json:"href"`
 }
+
+// Response mapped result of a query, Items is set for collections and Item for single entities
+// Links and Metadata are set for collections of sliced queries
+type Response struct {
+	BasePath   string
+	Model      interface{}
+	Collection bool
+	Item       interface{}
+	Items      interface{}
+	Links      Links
+	Metadata   *CollectionOpts
+}
+
+// body in the default json format, the embedded items of a collection or the entity
+func (r *Response) body() interface{} {
+	if !r.Collection {
+		return r.Item
+	}
+
+	return r.collection()
+}
+
+func (r *Response) collection() *Collection {
+	collection := Collection{
+		LinkOpts: LinkOpts{r.Links},
+		Embedded: Embedded{
+			"items": r.Items,
+		},
+	}
+	if r.Metadata != nil {
+		collection.Metadata = *r.Metadata
+	}
+
+	return &collection
+}
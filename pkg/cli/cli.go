@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"text/tabwriter"
+
+	api "github.com/mangalores/go-api-skeleton/pkg/api/echo"
+	"github.com/mangalores/go-api-skeleton/pkg/config"
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/mangalores/go-api-skeleton/pkg/migrations"
+	"gorm.io/gorm"
+)
+
+// RoutesFunc route handlers of the service, db is nil when routes are bound only to be listed
+type RoutesFunc func(c *config.Config, db *gorm.DB) []api.RouteHandler
+
+// Command Run receives the arguments following the command name
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	Run         func(ctx context.Context, args []string) error
+}
+
+type UnknownCommandErr struct {
+	name string
+}
+
+func (e UnknownCommandErr) Error() string {
+	return fmt.Sprintf("unknown command %s", e.name)
+}
+
+func NewUnknownCommandErr(name string) UnknownCommandErr {
+	return UnknownCommandErr{name}
+}
+
+// CLI entrypoint of services, provides serve, migrate, routes and config commands and takes service specific ones
+// global flags -env and -config name the .env and yaml files the config is loaded from
+type CLI struct {
+	name       string
+	out        io.Writer
+	routes     RoutesFunc
+	migrations []migrations.Migration
+	commands   map[string]*Command
+	envFile    string
+	yamlFile   string
+	config     *config.Config
+	conn       *gorm.DB
+}
+
+func New(name string, routes RoutesFunc) *CLI {
+	c := &CLI{
+		name:     name,
+		out:      os.Stdout,
+		routes:   routes,
+		commands: make(map[string]*Command),
+	}
+	c.registerDefaultCommands()
+
+	return c
+}
+
+// SetOutput writer of command output, stdout by default
+func (c *CLI) SetOutput(out io.Writer) {
+	c.out = out
+}
+
+func (c *CLI) Output() io.Writer {
+	return c.out
+}
+
+// AddMigrations registers migrations run by the migrate command
+func (c *CLI) AddMigrations(migrations ...migrations.Migration) {
+	c.migrations = append(c.migrations, migrations...)
+}
+
+// AddCommand adds or replaces a command
+func (c *CLI) AddCommand(cmd *Command) {
+	c.commands[cmd.Name] = cmd
+}
+
+// Config loaded once from environment and files
+func (c *CLI) Config() (*config.Config, error) {
+	if c.config != nil {
+		return c.config, nil
+	}
+
+	cfg := &config.Config{}
+	err := config.NewLoader().AddEnvFile(c.envFile).AddYAMLFile(c.yamlFile).Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.config = cfg
+
+	return cfg, nil
+}
+
+// DB connected once with the loaded config
+func (c *CLI) DB() (*gorm.DB, error) {
+	if c.conn != nil {
+		return c.conn, nil
+	}
+
+	cfg, err := c.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := db.NewDatabase(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+
+	return conn, nil
+}
+
+// Main runs the command of the process arguments until interrupted, exits with 1 on errors
+func (c *CLI) Main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := c.Run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+// Run parses the global flags and runs the named command
+// app [-env .env] [-config config.yaml] <command> [args]
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	flags.StringVar(&c.envFile, "env", ".env", "env file to load the config from")
+	flags.StringVar(&c.yamlFile, "config", "config.yaml", "yaml file to load the config from")
+	flags.Usage = func() { c.usage(flags) }
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		c.usage(flags)
+		return nil
+	}
+
+	cmd, ok := c.commands[flags.Arg(0)]
+	if !ok {
+		return NewUnknownCommandErr(flags.Arg(0))
+	}
+
+	return cmd.Run(ctx, flags.Args()[1:])
+}
+
+func (c *CLI) usage(flags *flag.FlagSet) {
+	fmt.Fprintf(c.out, "usage: %s [flags] <command> [args]\n\nflags:\n", c.name)
+	flags.PrintDefaults()
+	fmt.Fprintln(c.out, "\ncommands:")
+
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\t%s\n", c.commands[name].Usage, c.commands[name].Description)
+	}
+	w.Flush()
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	api "github.com/mangalores/go-api-skeleton/pkg/api/echo"
+	rh "github.com/mangalores/go-api-skeleton/pkg/api/response_handler"
+	"github.com/mangalores/go-api-skeleton/pkg/config"
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/mangalores/go-api-skeleton/pkg/migrations"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type MockThing struct {
+	ID int `json:"id"`
+}
+
+func newTestCLI(t *testing.T) (*CLI, *bytes.Buffer) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_NAME", "file::memory:")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("SWAGGER_ENABLED", "false")
+
+	c := New("app", func(c *config.Config, conn *gorm.DB) []api.RouteHandler {
+		return []api.RouteHandler{api.NewResource[MockThing]("/things", db.NewQueryManager(conn), rh.NewResponseHandler())}
+	})
+	out := &bytes.Buffer{}
+	c.SetOutput(out)
+
+	return c, out
+}
+
+func run(c *CLI, args ...string) error {
+	return c.Run(context.Background(), append([]string{"-env", "does-not-exist.env", "-config", "does-not-exist.yaml"}, args...))
+}
+
+func TestCLI_Routes(t *testing.T) {
+	c, out := newTestCLI(t)
+
+	assert.Nil(t, run(c, "routes"))
+	assert.Regexp(t, `GET\s+/things\s+`, out.String())
+	assert.Regexp(t, `DELETE\s+/things/:id\s+`, out.String())
+}
+
+func TestCLI_ConfigPrint(t *testing.T) {
+	c, out := newTestCLI(t)
+
+	assert.Nil(t, run(c, "config", "print"))
+	assert.Contains(t, out.String(), "DB_DRIVER=sqlite\n")
+	assert.Contains(t, out.String(), "DB_PASSWORD=******\n")
+	assert.Contains(t, out.String(), "DB_DSN=\n")
+	assert.NotContains(t, out.String(), "secret")
+
+	assert.NotNil(t, run(c, "config"))
+}
+
+func TestCLI_Migrate(t *testing.T) {
+	c, out := newTestCLI(t)
+	c.AddMigrations(migrations.Migration{Version: 1, Name: "create_things", Up: func(tx *gorm.DB) error {
+		return tx.Exec("CREATE TABLE things (id INTEGER PRIMARY KEY)").Error
+	}})
+
+	assert.Nil(t, run(c, "migrate", "up"))
+	assert.Equal(t, "applied 1 create_things\n", out.String())
+}
+
+func TestCLI_Commands(t *testing.T) {
+	c, out := newTestCLI(t)
+	c.AddCommand(&Command{Name: "hello", Usage: "hello", Run: func(ctx context.Context, args []string) error {
+		out.WriteString("hello " + args[0])
+		return nil
+	}})
+
+	assert.Nil(t, run(c, "hello", "world"))
+	assert.Equal(t, "hello world", out.String())
+
+	assert.Equal(t, NewUnknownCommandErr("unknown"), run(c, "unknown"))
+
+	out.Reset()
+	assert.Nil(t, run(c))
+	assert.Contains(t, out.String(), "config print")
+}
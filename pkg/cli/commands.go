@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	api "github.com/mangalores/go-api-skeleton/pkg/api/echo"
+	"github.com/mangalores/go-api-skeleton/pkg/config"
+	"github.com/mangalores/go-api-skeleton/pkg/migrations"
+	"gorm.io/gorm"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+func (c *CLI) registerDefaultCommands() {
+	c.AddCommand(&Command{
+		Name:        "serve",
+		Usage:       "serve",
+		Description: "start the http server",
+		Run:         c.serve,
+	})
+	c.AddCommand(&Command{
+		Name:        "migrate",
+		Usage:       migrations.CommandUsage,
+		Description: "apply, revert or list database migrations",
+		Run:         c.migrate,
+	})
+	c.AddCommand(&Command{
+		Name:        "routes",
+		Usage:       "routes",
+		Description: "list the bound routes",
+		Run:         c.listRoutes,
+	})
+	c.AddCommand(&Command{
+		Name:        "config",
+		Usage:       "config print",
+		Description: "print the loaded config, secrets redacted",
+		Run:         c.printConfig,
+	})
+}
+
+// serve until ctx is done, active requests are given shutdownTimeout to finish
+func (c *CLI) serve(ctx context.Context, args []string) error {
+	cfg, err := c.Config()
+	if err != nil {
+		return err
+	}
+	conn, err := c.DB()
+	if err != nil {
+		return err
+	}
+
+	app := c.app(cfg, conn)
+	served := make(chan error, 1)
+	go func() {
+		served <- app.Serve()
+	}()
+
+	select {
+	case err = <-served:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err = app.Shutdown(shutdownCtx)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func (c *CLI) migrate(ctx context.Context, args []string) error {
+	conn, err := c.DB()
+	if err != nil {
+		return err
+	}
+
+	m := migrations.NewMigrator(conn)
+	if err = m.Register(c.migrations...); err != nil {
+		return err
+	}
+
+	return migrations.Command(ctx, m, args, c.out)
+}
+
+// listRoutes binds the routes without database connection
+func (c *CLI) listRoutes(ctx context.Context, args []string) error {
+	cfg, err := c.Config()
+	if err != nil {
+		return err
+	}
+
+	routes := c.app(cfg, nil).Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER")
+	for _, route := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", route.Method, route.Path, route.Name)
+	}
+
+	return w.Flush()
+}
+
+func (c *CLI) printConfig(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return errors.New("usage: config print")
+	}
+
+	cfg, err := c.Config()
+	if err != nil {
+		return err
+	}
+
+	return config.Print(c.out, cfg)
+}
+
+func (c *CLI) app(cfg *config.Config, conn *gorm.DB) *api.App {
+	e := echo.New()
+	e.HideBanner = true
+
+	app := api.NewAppFromConfig(e, cfg.App)
+	if c.routes != nil {
+		for _, h := range c.routes(cfg, conn) {
+			app.BindRoutes(h)
+		}
+	}
+
+	return app
+}
@@ -0,0 +1,35 @@
+package config
+
+import "github.com/mangalores/go-api-skeleton/pkg/db"
+
+// App settings of the http server
+type App struct {
+	Addr     string `envconfig:"APP_ADDR" default:":8080"`
+	LogLevel string `envconfig:"LOG_LEVEL" default:"info"`
+	CORS     CORS
+	Swagger  bool `envconfig:"SWAGGER_ENABLED" default:"true"`
+	// ProblemBaseURI prefix of the type of problem+json error responses, /problems/ by default
+	ProblemBaseURI string `envconfig:"PROBLEM_BASE_URI"`
+}
+
+// CORS empty lists keep the defaults of the echo cors middleware
+type CORS struct {
+	AllowOrigins     []string `envconfig:"CORS_ALLOW_ORIGINS"`
+	AllowMethods     []string `envconfig:"CORS_ALLOW_METHODS"`
+	AllowHeaders     []string `envconfig:"CORS_ALLOW_HEADERS"`
+	AllowCredentials bool     `envconfig:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           int      `envconfig:"CORS_MAX_AGE"`
+}
+
+type Config struct {
+	App App
+	DB  db.Config
+}
+
+// Load the config from the environment, .env and config.yaml of the working directory
+func Load() (*Config, error) {
+	c := &Config{}
+	err := NewLoader().AddEnvFile(".env").AddYAMLFile("config.yaml").Load(c)
+
+	return c, err
+}
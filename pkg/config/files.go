@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readEnvFile reads KEY=VALUE lines, blank lines, # comments and export prefixes are skipped
+// values may be quoted, escapes like \n are resolved in double quoted values only
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		values[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+	}
+
+	return values, scanner.Err()
+}
+
+func unquote(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+
+	switch {
+	case value[0] == '"' && value[len(value)-1] == '"':
+		return strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1]
+	default:
+		return value
+	}
+}
+
+// readYAMLFile flattens the yaml document into upper case keys joined by _, lists are joined by ,
+// db: {host: localhost}, cors: {allow_origins: [a, b]} => DB_HOST=localhost, CORS_ALLOW_ORIGINS=a,b
+func readYAMLFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	document := make(map[string]interface{})
+	if err = yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten("", document, values)
+
+	return values, nil
+}
+
+func flatten(prefix string, value interface{}, values map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			name := strings.ToUpper(key)
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+			flatten(name, nested, values)
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		values[prefix] = strings.Join(items, ",")
+	case nil:
+		values[prefix] = ""
+	default:
+		values[prefix] = fmt.Sprint(v)
+	}
+}
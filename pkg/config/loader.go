@@ -0,0 +1,278 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	keyTag      = "envconfig"
+	defaultTag  = "default"
+	requiredTag = "required"
+	fileSuffix  = "_FILE"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// RequiredKeys implemented by configs whose required keys depend on other values
+type RequiredKeys interface {
+	RequiredKeys() []string
+}
+
+type ValidationErr struct {
+	missing []string
+	invalid []string
+}
+
+func (e ValidationErr) Error() string {
+	messages := make([]string, 0, 2)
+	if len(e.missing) > 0 {
+		messages = append(messages, fmt.Sprintf("missing config keys %s", strings.Join(e.missing, ", ")))
+	}
+	if len(e.invalid) > 0 {
+		messages = append(messages, fmt.Sprintf("invalid values of config keys %s", strings.Join(e.invalid, ", ")))
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+func (e ValidationErr) Missing() []string {
+	return e.missing
+}
+
+func (e ValidationErr) Invalid() []string {
+	return e.invalid
+}
+
+func NewValidationErr(missing []string, invalid []string) ValidationErr {
+	sort.Strings(missing)
+	sort.Strings(invalid)
+
+	return ValidationErr{slices.Compact(missing), slices.Compact(invalid)}
+}
+
+// Loader populates the envconfig tagged fields of config structs
+// values are looked up in the environment, then .env files, then yaml files, then the default tag
+// for every key KEY_FILE names a file containing the value instead, e.g. DB_PASSWORD_FILE=/run/secrets/db
+type Loader struct {
+	lookup    func(key string) (string, bool)
+	envFiles  []string
+	yamlFiles []string
+}
+
+func NewLoader() *Loader {
+	return &Loader{
+		lookup: os.LookupEnv,
+	}
+}
+
+// AddEnvFile adds a .env file, files that do not exist are skipped, earlier files take precedence
+func (l *Loader) AddEnvFile(paths ...string) *Loader {
+	l.envFiles = append(l.envFiles, paths...)
+
+	return l
+}
+
+// AddYAMLFile adds a yaml file, files that do not exist are skipped, earlier files take precedence
+// nested keys are joined by _, e.g. db: {host: localhost} => DB_HOST
+func (l *Loader) AddYAMLFile(paths ...string) *Loader {
+	l.yamlFiles = append(l.yamlFiles, paths...)
+
+	return l
+}
+
+// Load populates targets, pointers to config structs, all missing and invalid keys are reported at once
+func (l *Loader) Load(targets ...interface{}) error {
+	sources, err := l.sources()
+	if err != nil {
+		return err
+	}
+
+	missing := make([]string, 0)
+	invalid := make([]string, 0)
+	for _, target := range targets {
+		v := reflect.ValueOf(target)
+		if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+			return errors.New("config target must be a pointer to a struct")
+		}
+
+		m, i := populate(v.Elem(), sources, make(map[string]bool))
+		missing = append(missing, m...)
+		invalid = append(invalid, i...)
+	}
+
+	if len(missing) > 0 || len(invalid) > 0 {
+		return NewValidationErr(missing, invalid)
+	}
+
+	return nil
+}
+
+func (l *Loader) sources() ([]source, error) {
+	sources := []source{l.lookup}
+
+	for _, path := range l.envFiles {
+		values, err := readEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, mapSource(values))
+	}
+
+	for _, path := range l.yamlFiles {
+		values, err := readYAMLFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, mapSource(values))
+	}
+
+	return sources, nil
+}
+
+type source func(key string) (string, bool)
+
+func mapSource(values map[string]string) source {
+	return func(key string) (string, bool) {
+		v, ok := values[key]
+		return v, ok
+	}
+}
+
+// lookup value of key or the content of the file named by KEY_FILE in the first source containing either
+func lookup(sources []source, key string) (string, bool, error) {
+	for _, s := range sources {
+		if v, ok := s(key); ok {
+			return v, true, nil
+		}
+		if path, ok := s(key + fileSuffix); ok {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return "", false, err
+			}
+			return strings.TrimRight(string(content), "\r\n"), true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// populate sets the tagged fields of v, nested structs without tag are populated recursively
+// resolved keys are tracked to check the RequiredKeys of v
+func populate(v reflect.Value, sources []source, resolved map[string]bool) (missing []string, invalid []string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := field.Tag.Get(keyTag)
+		if key == "" {
+			if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+				m, inv := populate(v.Field(i), sources, resolved)
+				missing = append(missing, m...)
+				invalid = append(invalid, inv...)
+			}
+			continue
+		}
+
+		value, ok, err := lookup(sources, key)
+		if err != nil {
+			invalid = append(invalid, key)
+			continue
+		}
+		if !ok {
+			value, ok = field.Tag.Lookup(defaultTag)
+		}
+		if !ok || value == "" {
+			if field.Tag.Get(requiredTag) == "true" {
+				missing = append(missing, key)
+			}
+		}
+		// empty values leave non string fields unset
+		if !ok || (value == "" && field.Type.Kind() != reflect.String) {
+			continue
+		}
+
+		if err = setValue(v.Field(i), value); err != nil {
+			invalid = append(invalid, key)
+			continue
+		}
+		resolved[key] = value != ""
+	}
+
+	if r, ok := v.Addr().Interface().(RequiredKeys); ok {
+		for _, key := range r.RequiredKeys() {
+			if !resolved[key] {
+				missing = append(missing, key)
+			}
+		}
+	}
+
+	return
+}
+
+// setValue parses value into the type of v, slices are comma separated
+func setValue(v reflect.Value, value string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts := make([]string, 0)
+		if value != "" {
+			parts = strings.Split(value, ",")
+		}
+		s := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(s.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+	default:
+		return fmt.Errorf("unsupported config field type %s", v.Type())
+	}
+
+	return nil
+}
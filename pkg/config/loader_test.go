@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mangalores/go-api-skeleton/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func newTestLoader(env map[string]string) *Loader {
+	l := NewLoader()
+	l.lookup = mapSource(env)
+
+	return l
+}
+
+func TestLoader_Load(t *testing.T) {
+	secret := writeFile(t, "secret", "s3cret\n")
+	envFile := writeFile(t, ".env", "# comment\nexport DB_USER=api\nDB_HOST=\"from env file\"\nDB_PASSWORD_FILE="+secret+"\n")
+	yamlFile := writeFile(t, "config.yaml", "db:\n  host: from yaml\n  name: api\n  max_open_conns: 5\ncors:\n  allow_origins: [a.com, b.com]\n")
+
+	c := &Config{}
+	err := newTestLoader(map[string]string{"DB_CONN_MAX_LIFETIME": "1m", "APP_ADDR": ":9000"}).
+		AddEnvFile(envFile, "does-not-exist.env").
+		AddYAMLFile(yamlFile).
+		Load(c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, App{Addr: ":9000", LogLevel: "info", Swagger: true, CORS: CORS{AllowOrigins: []string{"a.com", "b.com"}}}, c.App)
+	assert.Equal(t, "from env file", c.DB.Host)
+	assert.Equal(t, "api", c.DB.User)
+	assert.Equal(t, "s3cret", c.DB.Password)
+	assert.Equal(t, "api", c.DB.DatabaseName)
+	assert.Equal(t, db.Postgres, c.DB.Driver)
+	assert.Equal(t, 5, c.DB.MaxOpenConns)
+	assert.Equal(t, time.Minute, c.DB.ConnMaxLifetime)
+}
+
+func TestLoader_Validation(t *testing.T) {
+	type Required struct {
+		Token string `envconfig:"TOKEN" required:"true"`
+		Port  int    `envconfig:"PORT"`
+	}
+
+	err := newTestLoader(map[string]string{"PORT": "abc", "DB_PASSWORD_FILE": "/does/not/exist"}).Load(&Config{}, &Required{})
+	assert.Equal(t, NewValidationErr([]string{"DB_HOST", "DB_NAME", "DB_USER", "TOKEN"}, []string{"DB_PASSWORD", "PORT"}), err)
+
+	err = newTestLoader(map[string]string{"DB_DRIVER": "sqlite", "DB_NAME": ":memory:"}).Load(&Config{})
+	assert.Nil(t, err)
+}
+
+func TestUnquote(t *testing.T) {
+	tests := [][]string{
+		{`plain`, `plain`},
+		{`"a\nb"`, "a\nb"},
+		{`'a\nb'`, `a\nb`},
+		{`"`, `"`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test[1], unquote(test[0]))
+	}
+}
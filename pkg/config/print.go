@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	secretTag = "secret"
+	redacted  = "******"
+)
+
+// Print writes the envconfig tagged fields of config as KEY=VALUE lines, values of fields tagged secret:"true" are redacted
+func Print(out io.Writer, config interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(config))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("config must be a struct, got %s", v.Type())
+	}
+
+	return printFields(out, v)
+}
+
+func printFields(out io.Writer, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := field.Tag.Get(keyTag)
+		if key == "" {
+			if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+				if err := printFields(out, v.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		value := formatValue(v.Field(i))
+		if field.Tag.Get(secretTag) == "true" && value != "" {
+			value = redacted
+		}
+		if _, err := fmt.Fprintf(out, "%s=%s\n", key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatValue inverse of setValue, slices are joined by ,
+func formatValue(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	if v.Kind() == reflect.Slice {
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, formatValue(v.Index(i)))
+		}
+		return strings.Join(items, ",")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
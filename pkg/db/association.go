@@ -0,0 +1,123 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormSchema "gorm.io/gorm/schema"
+)
+
+const pathSeparator = "."
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+type UnknownRelationErr struct {
+	name string
+}
+
+func (e UnknownRelationErr) Error() string {
+	return fmt.Sprintf("unknown relation %s", e.name)
+}
+
+func NewUnknownRelationErr(name string) UnknownRelationErr {
+	return UnknownRelationErr{name}
+}
+
+// buildPathCondition
+// resolves field paths over associations into EXISTS subqueries, e.g. for posts filtered by Author.Name:
+// EXISTS (SELECT 1 FROM authors authors_1 WHERE authors_1.id = posts.author_id AND authors_1.name = ?)
+// soft deleted related rows are excluded like gorm does for queries of the model itself
+func buildPathCondition(filter Filter, path []string, schema *gormSchema.Schema, table string, depth int) (string, []interface{}, error) {
+	if len(path) == 1 {
+		field := schema.FieldsByName[path[0]]
+		if field == nil {
+			return "", nil, fmt.Errorf("unknown field name %s", filter.FieldName)
+		}
+
+		return renderCondition(filter, clause.Column{Table: table, Name: field.DBName})
+	}
+
+	rel, ok := schema.Relationships.Relations[path[0]]
+	if !ok {
+		return "", nil, NewUnknownRelationErr(path[0])
+	}
+
+	parent := table
+	if parent == "" {
+		parent = clause.CurrentTable
+	}
+	alias := fmt.Sprintf("%s_%d", rel.FieldSchema.Table, depth)
+
+	condition, args, err := buildPathCondition(filter, path[1:], rel.FieldSchema, alias, depth+1)
+	if err != nil {
+		return "", nil, err
+	}
+
+	from, joins, joinArgs := buildRelationJoin(rel, parent, alias, depth)
+	if field := softDeleteField(rel.FieldSchema); field != nil {
+		joins = append(joins, "? IS NULL")
+		joinArgs = append(joinArgs, clause.Column{Table: alias, Name: field.DBName})
+	}
+	joins = append(joins, condition)
+
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s)", from, strings.Join(joins, " AND ")), append(joinArgs, args...), nil
+}
+
+// buildRelationJoin from clause and conditions joining the related table as alias to the parent table
+func buildRelationJoin(rel *gormSchema.Relationship, parent string, alias string, depth int) (from string, conditions []string, args []interface{}) {
+	related := clause.Table{Name: rel.FieldSchema.Table, Alias: alias}
+
+	if rel.JoinTable == nil {
+		for _, ref := range rel.References {
+			switch {
+			case ref.OwnPrimaryKey:
+				conditions = append(conditions, "? = ?")
+				args = append(args, clause.Column{Table: alias, Name: ref.ForeignKey.DBName}, clause.Column{Table: parent, Name: ref.PrimaryKey.DBName})
+			case ref.PrimaryValue != "":
+				conditions = append(conditions, "? = ?")
+				args = append(args, clause.Column{Table: alias, Name: ref.ForeignKey.DBName}, ref.PrimaryValue)
+			default:
+				conditions = append(conditions, "? = ?")
+				args = append(args, clause.Column{Table: alias, Name: ref.PrimaryKey.DBName}, clause.Column{Table: parent, Name: ref.ForeignKey.DBName})
+			}
+		}
+
+		return "?", conditions, append([]interface{}{related}, args...)
+	}
+
+	// many to many relations join the related table over the join table
+	joinAlias := fmt.Sprintf("%s_%d", rel.JoinTable.Table, depth)
+	on := make([]string, 0)
+	onArgs := make([]interface{}, 0)
+	for _, ref := range rel.References {
+		switch {
+		case ref.OwnPrimaryKey:
+			conditions = append(conditions, "? = ?")
+			args = append(args, clause.Column{Table: joinAlias, Name: ref.ForeignKey.DBName}, clause.Column{Table: parent, Name: ref.PrimaryKey.DBName})
+		case ref.PrimaryValue != "":
+			conditions = append(conditions, "? = ?")
+			args = append(args, clause.Column{Table: joinAlias, Name: ref.ForeignKey.DBName}, ref.PrimaryValue)
+		default:
+			on = append(on, "? = ?")
+			onArgs = append(onArgs, clause.Column{Table: joinAlias, Name: ref.ForeignKey.DBName}, clause.Column{Table: alias, Name: ref.PrimaryKey.DBName})
+		}
+	}
+
+	fromArgs := append([]interface{}{clause.Table{Name: rel.JoinTable.Table, Alias: joinAlias}, related}, onArgs...)
+
+	return "? JOIN ? ON " + strings.Join(on, " AND "), conditions, append(fromArgs, args...)
+}
+
+// softDeleteField gorm.DeletedAt field of soft deleted models, nil for other models
+func softDeleteField(schema *gormSchema.Schema) *gormSchema.Field {
+	for _, field := range schema.Fields {
+		if field.FieldType == deletedAtType {
+			return field
+		}
+	}
+
+	return nil
+}
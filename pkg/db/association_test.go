@@ -0,0 +1,94 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type relatedCompany struct {
+	ID   int
+	Name string
+}
+
+type relatedAuthor struct {
+	ID        int
+	Name      string
+	CompanyID int
+	Company   relatedCompany
+	Posts     []relatedPost `gorm:"foreignKey:AuthorID"`
+}
+
+type relatedPost struct {
+	ID       int
+	Title    string
+	AuthorID int
+	Author   relatedAuthor
+	Tags     []relatedTag `gorm:"many2many:related_post_tags"`
+}
+
+type relatedTag struct {
+	ID        int
+	Label     string
+	DeletedAt gorm.DeletedAt
+}
+
+func TestQueryHandler_HandleAssociationFilters(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "association.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&relatedCompany{}, &relatedAuthor{}, &relatedPost{}, &relatedTag{}))
+
+	acme, other := relatedCompany{Name: "acme"}, relatedCompany{Name: "other"}
+	alice, bob := relatedAuthor{Name: "alice", Company: acme}, relatedAuthor{Name: "bob", Company: other}
+	news, old := relatedTag{Label: "news"}, relatedTag{Label: "old"}
+	posts := []relatedPost{
+		{Title: "first", Author: alice, Tags: []relatedTag{news}},
+		{Title: "second", Author: bob, Tags: []relatedTag{old}},
+	}
+	assert.Nil(t, conn.Create(&posts).Error)
+	assert.Nil(t, conn.Create(&relatedPost{Title: "third", AuthorID: posts[0].AuthorID, Tags: []relatedTag{posts[1].Tags[0]}}).Error)
+	assert.Nil(t, conn.Delete(&posts[1].Tags[0]).Error)
+
+	h := NewQueryHandler(conn)
+
+	postTests := []struct {
+		filter   Filter
+		expected []string
+	}{
+		// belongs to
+		{Filter{"Author.Name", "=", "alice"}, []string{"first", "third"}},
+		{Filter{"Author.Company.Name", "=", "other"}, []string{"second"}},
+		// many to many, soft deleted tags do not match
+		{Filter{"Tags.Label", "=", "news"}, []string{"first"}},
+		{Filter{"Tags.Label", "=", "old"}, []string{}},
+	}
+
+	for _, test := range postTests {
+		q := NewCollectionQuery(&[]relatedPost{})
+		q.SetFilters([]Filter{test.filter})
+		h.Handle(q)
+		assert.Nil(t, q.Error(), test.filter)
+
+		titles := make([]string, 0)
+		for _, post := range *q.Result().(*[]relatedPost) {
+			titles = append(titles, post.Title)
+		}
+		assert.Equal(t, test.expected, titles, test.filter)
+	}
+
+	// has many
+	q := NewCollectionQuery(&[]relatedAuthor{})
+	q.SetFilters([]Filter{{"Posts.Title", "=", "second"}})
+	h.Handle(q)
+	assert.Nil(t, q.Error())
+	authors := *q.Result().(*[]relatedAuthor)
+	assert.Len(t, authors, 1)
+	assert.Equal(t, "bob", authors[0].Name)
+
+	q = NewCollectionQuery(&[]relatedPost{})
+	q.SetFilters([]Filter{{"Editor.Name", "=", "alice"}})
+	h.Handle(q)
+	assert.Equal(t, NewUnknownRelationErr("Editor"), q.Error())
+}
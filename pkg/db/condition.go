@@ -0,0 +1,89 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/clause"
+)
+
+const (
+	IsNull     = "IS NULL"
+	IsNotNull  = "IS NOT NULL"
+	Between    = "BETWEEN"
+	NotBetween = "NOT BETWEEN"
+	Like       = "LIKE"
+	ILike      = "ILIKE"
+)
+
+// likeEscape escape character of like patterns, explicitly declared as sqlite has no default one
+const likeEscape = `\`
+
+// ConditionFunc renders the sql condition and its arguments for a column and filter value,
+// the column is passed as argument to be quoted by the dialect, e.g. "? IS NULL", column
+type ConditionFunc func(column clause.Column, value interface{}) (string, []interface{}, error)
+
+// conditions operators not following the default "column operator ?" form
+var conditions = map[string]ConditionFunc{
+	IsNull:     unaryCondition(IsNull),
+	IsNotNull:  unaryCondition(IsNotNull),
+	Between:    rangeCondition(Between),
+	NotBetween: rangeCondition(NotBetween),
+	Like:       patternCondition(Like),
+	ILike:      foldedPatternCondition(),
+}
+
+type InvalidConditionValueErr struct {
+	operator string
+	value    interface{}
+}
+
+func (e InvalidConditionValueErr) Error() string {
+	return fmt.Sprintf("invalid value %v for operator %s", e.value, e.operator)
+}
+
+func NewInvalidConditionValueErr(operator string, value interface{}) InvalidConditionValueErr {
+	return InvalidConditionValueErr{operator, value}
+}
+
+// RegisterCondition register how filters with the given operator are rendered, e.g. for custom query builder operators
+func RegisterCondition(operator string, fn ConditionFunc) {
+	conditions[operator] = fn
+}
+
+// unaryCondition
+// column IS NULL
+func unaryCondition(operator string) ConditionFunc {
+	return func(column clause.Column, value interface{}) (string, []interface{}, error) {
+		return fmt.Sprintf("? %s", operator), []interface{}{column}, nil
+	}
+}
+
+// rangeCondition
+// []int{1, 5} => column BETWEEN ? AND ?, args 1, 5
+func rangeCondition(operator string) ConditionFunc {
+	return func(column clause.Column, value interface{}) (string, []interface{}, error) {
+		v := reflect.ValueOf(value)
+		if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Len() != 2 {
+			return "", nil, NewInvalidConditionValueErr(operator, value)
+		}
+
+		return fmt.Sprintf("? %s ? AND ?", operator), []interface{}{column, v.Index(0).Interface(), v.Index(1).Interface()}, nil
+	}
+}
+
+// patternCondition
+// column LIKE ? ESCAPE ?, args pattern, \
+func patternCondition(operator string) ConditionFunc {
+	return func(column clause.Column, value interface{}) (string, []interface{}, error) {
+		return fmt.Sprintf("? %s ? ESCAPE ?", operator), []interface{}{column, value, likeEscape}, nil
+	}
+}
+
+// foldedPatternCondition case insensitive pattern, rendered portably as ILIKE is specific to postgres
+// LOWER(column) LIKE LOWER(?) ESCAPE ?, args pattern, \
+func foldedPatternCondition() ConditionFunc {
+	return func(column clause.Column, value interface{}) (string, []interface{}, error) {
+		return fmt.Sprintf("LOWER(?) %s LOWER(?) ESCAPE ?", Like), []interface{}{column, value, likeEscape}, nil
+	}
+}
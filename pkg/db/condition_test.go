@@ -0,0 +1,41 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryHandler_HandlePatternConditions(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "condition.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	for _, title := range []string{"Foo_bar", "foobar", "FOO%", "baz"} {
+		assert.Nil(t, conn.Create(&streamedThing{Title: title}).Error)
+	}
+
+	tests := []struct {
+		filter   Filter
+		expected []string
+	}{
+		{Filter{"Title", Like, []string{`Foo\_%`}}, []string{"Foo_bar"}},
+		{Filter{"Title", ILike, []string{"foo%"}}, []string{"Foo_bar", "foobar", "FOO%"}},
+		{Filter{"Title", ILike, []string{`foo\_%`}}, []string{"Foo_bar"}},
+		{Filter{"Title", ILike, []string{`%\%`}}, []string{"FOO%"}},
+	}
+
+	h := NewQueryHandler(conn)
+	for _, test := range tests {
+		q := NewCollectionQuery(&[]streamedThing{})
+		q.SetFilters([]Filter{test.filter})
+		h.Handle(q)
+		assert.Nil(t, q.Error())
+
+		titles := make([]string, 0)
+		for _, thing := range *q.Result().(*[]streamedThing) {
+			titles = append(titles, thing.Title)
+		}
+		assert.Equal(t, test.expected, titles, test.filter)
+	}
+}
@@ -1,11 +1,46 @@
 package db
 
+import (
+	"strings"
+	"time"
+)
+
+// Config Driver is one of the registered drivers, postgres by default
+// DSN replaces the connection string built from the other options if set
+// pool settings left 0 keep the database/sql defaults
+// secret values are redacted when the config is printed
+// connecting is retried ConnectRetries times, waiting RetryBackoff doubled after every attempt
 type Config struct {
-	Host         string `envconfig:"DB_HOST"`
-	Driver       string `envconfig:"DB_DRIVER"`
-	DatabaseName string `envconfig:"DB_NAME"`
-	User         string `envconfig:"DB_USER"`
-	Password     string `envconfig:"DB_PASSWORD"`
-	Port         string `envconfig:"DB_PORT"`
-	Logging      string `envconfig:"DB_LOGGING"`
+	Host            string `envconfig:"DB_HOST"`
+	Driver          string `envconfig:"DB_DRIVER" default:"postgres"`
+	DatabaseName    string `envconfig:"DB_NAME"`
+	User            string `envconfig:"DB_USER"`
+	Password        string `envconfig:"DB_PASSWORD" secret:"true"`
+	Port            string `envconfig:"DB_PORT"`
+	Logging         string `envconfig:"DB_LOGGING"`
+	DSN             string `envconfig:"DB_DSN" secret:"true"`
+	SSLMode         string `envconfig:"DB_SSLMODE"`
+	TimeZone        string `envconfig:"DB_TIMEZONE"`
+	SearchPath      string `envconfig:"DB_SEARCH_PATH"`
+	ApplicationName string `envconfig:"DB_APPLICATION_NAME"`
+
+	MaxOpenConns    int           `envconfig:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `envconfig:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `envconfig:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `envconfig:"DB_CONN_MAX_IDLE_TIME"`
+
+	ConnectRetries int           `envconfig:"DB_CONNECT_RETRIES"`
+	RetryBackoff   time.Duration `envconfig:"DB_RETRY_BACKOFF"`
+}
+
+// RequiredKeys keys to be set for the configured driver, none if DSN is set
+func (c Config) RequiredKeys() []string {
+	switch {
+	case c.DSN != "":
+		return []string{}
+	case strings.ToLower(c.Driver) == Sqlite:
+		return []string{"DB_NAME"}
+	default:
+		return []string{"DB_HOST", "DB_NAME", "DB_USER"}
+	}
 }
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+const defaultCountCap = 10000
+
+// buildCount sets the total of the slice according to its count strategy, must run before offset, limit and order are applied
+func buildCount(stmt *gorm.DB, sel *Slice) error {
+	switch sel.Count {
+	case CountNone:
+		return nil
+	case CountCapped:
+		return countCapped(stmt, sel)
+	case CountEstimate:
+		if stmt.Dialector.Name() == "postgres" {
+			return countEstimate(stmt, sel)
+		}
+		// estimates are only supported by postgres, fall back to exact count
+		sel.Count = CountExact
+	}
+
+	return stmt.Session(&gorm.Session{}).Count(&sel.Total).Error
+}
+
+// countCapped
+// SELECT count(*) FROM (SELECT 1 FROM things WHERE ... LIMIT cap + 1) capped
+func countCapped(stmt *gorm.DB, sel *Slice) error {
+	limit := sel.CountCap
+	if limit <= 0 {
+		limit = defaultCountCap
+	}
+
+	sub := stmt.Session(&gorm.Session{}).Select("1").Limit(int(limit + 1))
+	err := stmt.Session(&gorm.Session{NewDB: true}).Table("(?) capped", sub).Count(&sel.Total).Error
+	if err != nil {
+		return err
+	}
+
+	if sel.Total > limit {
+		sel.Total = limit
+		sel.Capped = true
+	}
+
+	return nil
+}
+
+// countEstimate rows estimated by the postgres planner
+func countEstimate(stmt *gorm.DB, sel *Slice) error {
+	var plan []byte
+	if err := explainStatement(stmt).Row().Scan(&plan); err != nil {
+		return err
+	}
+
+	rows, err := planRows(plan)
+	if err != nil {
+		return err
+	}
+	sel.Total = rows
+
+	return nil
+}
+
+// explainStatement the query built as sub statement, as is the capped count
+// EXPLAIN (FORMAT JSON) SELECT * FROM things WHERE ...
+func explainStatement(stmt *gorm.DB) *gorm.DB {
+	return stmt.Session(&gorm.Session{NewDB: true}).Raw("EXPLAIN (FORMAT JSON) ?", stmt.Session(&gorm.Session{}))
+}
+
+// planRows rows of the top node of a json query plan
+// [{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 42, ...}}] => 42
+func planRows(plan []byte) (int64, error) {
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, err
+	}
+	if len(explained) == 0 {
+		return 0, errors.New("empty query plan")
+	}
+
+	return int64(explained[0].Plan.Rows), nil
+}
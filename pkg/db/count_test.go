@@ -0,0 +1,87 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryHandler_HandleCount(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "count.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	for i := 1; i <= 10; i++ {
+		assert.Nil(t, conn.Create(&streamedThing{Title: fmt.Sprintf("t%d", i), Views: i % 3}).Error)
+	}
+
+	tests := []struct {
+		slice    *Slice
+		filters  []Filter
+		total    int64
+		count    CountStrategy
+		capped   bool
+		counted  bool
+		hasMore  bool
+		expected int
+	}{
+		{&Slice{Limit: 2, Count: CountExact}, nil, 10, CountExact, false, true, false, 2},
+		{&Slice{Limit: 2, Count: CountNone}, nil, 0, CountNone, false, false, true, 2},
+		{&Slice{Limit: 2, Count: CountCapped, CountCap: 5}, nil, 5, CountCapped, true, false, true, 2},
+		{&Slice{Limit: 2, Count: CountCapped, CountCap: 5}, []Filter{{"Views", "=", 1}}, 4, CountCapped, false, true, true, 2},
+		{&Slice{Offset: 2, Limit: 2, Count: CountCapped, CountCap: 5}, []Filter{{"Views", "=", 1}}, 4, CountCapped, false, true, false, 2},
+		{&Slice{Limit: 2, Count: CountCapped}, nil, 10, CountCapped, false, true, true, 2},
+		// estimates fall back to exact counts on sqlite
+		{&Slice{Limit: 2, Count: CountEstimate}, []Filter{{"Views", "=", 0}}, 3, CountExact, false, true, false, 2},
+	}
+
+	h := NewQueryHandler(conn)
+	for _, test := range tests {
+		q := NewCollectionQuery(&[]streamedThing{})
+		q.SetSlice(test.slice)
+		q.SetFilters(test.filters)
+
+		h.Handle(q)
+		assert.Nil(t, q.Error())
+		assert.Equal(t, test.total, test.slice.Total)
+		assert.Equal(t, test.count, test.slice.Count)
+		assert.Equal(t, test.capped, test.slice.Capped)
+		assert.Equal(t, test.counted, test.slice.Counted())
+		assert.Equal(t, test.hasMore, test.slice.HasMore)
+		assert.Len(t, *q.Result().(*[]streamedThing), test.expected)
+	}
+}
+
+func TestExplainStatement(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "explain.sqlite")})
+	assert.Nil(t, err)
+
+	stmt := conn.Model(&streamedThing{}).Where("views = ?", 1)
+	explained := explainStatement(stmt)
+
+	assert.Nil(t, explained.Error)
+	assert.Equal(t, "EXPLAIN (FORMAT JSON) SELECT * FROM `streamed_things` WHERE views = ?", explained.Statement.SQL.String())
+	assert.Equal(t, []interface{}{1}, explained.Statement.Vars)
+	// the query itself is not run
+	assert.Equal(t, int64(0), stmt.RowsAffected)
+}
+
+func TestPlanRows(t *testing.T) {
+	tests := []struct {
+		plan     string
+		expected int64
+		err      bool
+	}{
+		{`[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "things", "Plan Rows": 42, "Plan Width": 8}}]`, 42, false},
+		{`[{"Plan": {"Node Type": "Limit", "Plan Rows": 1e+06}}]`, 1000000, false},
+		{`[]`, 0, true},
+		{`{`, 0, true},
+	}
+
+	for _, test := range tests {
+		rows, err := planRows([]byte(test.plan))
+		assert.Equal(t, test.expected, rows)
+		assert.Equal(t, test.err, err != nil)
+	}
+}
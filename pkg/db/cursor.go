@@ -0,0 +1,256 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormSchema "gorm.io/gorm/schema"
+)
+
+// Cursor position in a keyset paginated collection, values of the sort fields of the row at the position
+type Cursor struct {
+	Fields   []string          `json:"f"`
+	Values   []json.RawMessage `json:"v"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+type InvalidCursorErr struct {
+	reason string
+}
+
+func (e InvalidCursorErr) Error() string {
+	return fmt.Sprintf("invalid cursor: %s", e.reason)
+}
+
+func NewInvalidCursorErr(reason string) InvalidCursorErr {
+	return InvalidCursorErr{reason}
+}
+
+// NullableKeysetFieldErr rows with NULL in a sort field have no position in the keyset and would never be paged to
+type NullableKeysetFieldErr struct {
+	field string
+}
+
+func (e NullableKeysetFieldErr) Error() string {
+	return fmt.Sprintf("cannot paginate by cursor sorted by nullable field %s", e.field)
+}
+
+func NewNullableKeysetFieldErr(field string) NullableKeysetFieldErr {
+	return NullableKeysetFieldErr{field}
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// EncodeCursor opaque url safe representation of the cursor
+func EncodeCursor(c Cursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, NewInvalidCursorErr("malformed encoding")
+	}
+
+	c := &Cursor{}
+	if err = json.Unmarshal(data, c); err != nil || len(c.Fields) == 0 || len(c.Fields) != len(c.Values) {
+		return nil, NewInvalidCursorErr("malformed content")
+	}
+
+	return c, nil
+}
+
+// keysetSort sort fields with the primary key appended as tie-breaker, so every row has a unique position
+func keysetSort(sorts []Sort, schema *gormSchema.Schema) []Sort {
+	keyset := append([]Sort{}, sorts...)
+
+	for _, field := range schema.PrimaryFields {
+		found := false
+		for _, sort := range sorts {
+			found = found || sort.FieldName == field.Name
+		}
+		if !found {
+			keyset = append(keyset, Sort{FieldName: field.Name, Direction: ASC})
+		}
+	}
+
+	return keyset
+}
+
+// buildKeyset
+// orders by the keyset and restricts to rows after the cursor, sort a asc, b desc with cursor values 1, 2:
+// WHERE (a > 1 OR (a = 1 AND b < 2)) ORDER BY a, b DESC
+// backward cursors invert the order, the result is reversed after fetching
+// nullable sort fields are refused, NULL neither compares greater nor less than the cursor values
+func buildKeyset(stmt *gorm.DB, sel *Slice, schema *gormSchema.Schema) error {
+	keyset := keysetSort(sel.Sort, schema)
+	backward := sel.Cursor != nil && sel.Cursor.Backward
+
+	for _, sort := range keyset {
+		if field := schema.FieldsByName[sort.FieldName]; field != nil && nullableField(field) {
+			return NewNullableKeysetFieldErr(sort.FieldName)
+		}
+	}
+
+	if backward {
+		keyset = invertSort(keyset)
+	}
+	if err := buildSort(stmt, keyset, schema); err != nil {
+		return err
+	}
+	if sel.probes() {
+		stmt.Limit(sel.Limit + 1)
+	}
+	if sel.Cursor == nil {
+		return nil
+	}
+
+	values, err := decodeCursorValues(sel.Cursor, keyset, schema)
+	if err != nil {
+		return err
+	}
+
+	conditions := make([]string, 0, len(keyset))
+	args := make([]interface{}, 0)
+	for i, sort := range keyset {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, "? = ?")
+			args = append(args, clause.Column{Name: schema.FieldsByName[keyset[j].FieldName].DBName}, values[j])
+		}
+
+		operator := ">"
+		if sort.Direction == DESC {
+			operator = "<"
+		}
+		parts = append(parts, fmt.Sprintf("? %s ?", operator))
+		args = append(args, clause.Column{Name: schema.FieldsByName[sort.FieldName].DBName}, values[i])
+
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	stmt.Where("("+strings.Join(conditions, " OR ")+")", args...)
+
+	return nil
+}
+
+func decodeCursorValues(cursor *Cursor, keyset []Sort, schema *gormSchema.Schema) ([]interface{}, error) {
+	if len(cursor.Fields) != len(keyset) {
+		return nil, NewInvalidCursorErr("sort does not match")
+	}
+
+	values := make([]interface{}, 0, len(keyset))
+	for i, sort := range keyset {
+		field := schema.FieldsByName[sort.FieldName]
+		if field == nil {
+			return nil, fmt.Errorf("unknown field name %s", sort.FieldName)
+		}
+		if cursor.Fields[i] != sort.FieldName {
+			return nil, NewInvalidCursorErr("sort does not match")
+		}
+
+		value := reflect.New(field.FieldType)
+		if err := json.Unmarshal(cursor.Values[i], value.Interface()); err != nil {
+			return nil, NewInvalidCursorErr("malformed value")
+		}
+
+		values = append(values, value.Elem().Interface())
+	}
+
+	return values, nil
+}
+
+// finishKeyset trims the probe row, restores the order of backward pages and sets the cursors of adjacent pages
+func finishKeyset(query SlicedQueryObject, result interface{}, schema *gormSchema.Schema) error {
+	sel := query.Slice()
+	rows := reflect.ValueOf(result).Elem()
+	backward := sel.Cursor != nil && sel.Cursor.Backward
+
+	hasMore := sel.probes() && rows.Len() > sel.Limit
+	if hasMore {
+		rows.Set(rows.Slice(0, sel.Limit))
+	}
+	sel.HasMore = hasMore
+	if backward {
+		reverseSlice(rows)
+	}
+
+	sel.NextCursor, sel.PrevCursor = "", ""
+	if rows.Len() == 0 {
+		return nil
+	}
+
+	keyset := keysetSort(sel.Sort, schema)
+	var err error
+
+	if (!backward && hasMore) || backward {
+		if sel.NextCursor, err = rowCursor(rows.Index(rows.Len()-1), keyset, schema, false); err != nil {
+			return err
+		}
+	}
+	if (backward && hasMore) || (!backward && sel.Cursor != nil) {
+		if sel.PrevCursor, err = rowCursor(rows.Index(0), keyset, schema, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rowCursor(row reflect.Value, keyset []Sort, schema *gormSchema.Schema, backward bool) (string, error) {
+	cursor := Cursor{Backward: backward}
+
+	for _, sort := range keyset {
+		value, _ := schema.FieldsByName[sort.FieldName].ValueOf(context.Background(), row)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		cursor.Fields = append(cursor.Fields, sort.FieldName)
+		cursor.Values = append(cursor.Values, data)
+	}
+
+	return EncodeCursor(cursor)
+}
+
+// nullableField fields of pointer or driver.Valuer types, e.g. *int or sql.NullString, unless declared not null
+func nullableField(field *gormSchema.Field) bool {
+	if field.NotNull || field.PrimaryKey {
+		return false
+	}
+
+	return field.FieldType.Kind() == reflect.Ptr || field.FieldType.Implements(valuerType)
+}
+
+func invertSort(sorts []Sort) []Sort {
+	inverted := make([]Sort, 0, len(sorts))
+	for _, sort := range sorts {
+		direction := DESC
+		if sort.Direction == DESC {
+			direction = ASC
+		}
+		inverted = append(inverted, Sort{FieldName: sort.FieldName, Direction: direction})
+	}
+
+	return inverted
+}
+
+func reverseSlice(v reflect.Value) {
+	swap := reflect.Swapper(v.Interface())
+	for i, j := 0, v.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rankedThing struct {
+	ID   int
+	Rank *int
+}
+
+// keysetPage ids of the page at cursor, the slice holds the cursors of the adjacent pages
+func keysetPage(t *testing.T, h *QueryHandler, sort []Sort, cursor string) ([]int, *Slice) {
+	slice := &Slice{Keyset: true, Limit: 4, Sort: sort}
+	if cursor != "" {
+		c, err := DecodeCursor(cursor)
+		assert.Nil(t, err)
+		slice.Cursor = c
+	}
+
+	q := NewCollectionQuery(&[]streamedThing{})
+	q.SetSlice(slice)
+	h.Handle(q)
+	assert.Nil(t, q.Error())
+
+	ids := make([]int, 0)
+	for _, thing := range *q.Result().(*[]streamedThing) {
+		ids = append(ids, thing.ID)
+	}
+
+	return ids, slice
+}
+
+func TestQueryHandler_HandleKeyset(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "keyset.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}, &rankedThing{}))
+	for i := 1; i <= 10; i++ {
+		assert.Nil(t, conn.Create(&streamedThing{Title: fmt.Sprintf("t%d", i), Views: i % 3}).Error)
+	}
+	h := NewQueryHandler(conn)
+
+	// views 2, 2, 2, 1 | 1, 1, 1, 0 | 0, 0, rows of equal views are ordered by the id
+	sort := []Sort{{"Views", DESC}}
+	pages := [][]int{{2, 5, 8, 1}, {4, 7, 10, 3}, {6, 9}}
+
+	cursor := ""
+	slices := make([]*Slice, 0)
+	for i, expected := range pages {
+		ids, slice := keysetPage(t, h, sort, cursor)
+		assert.Equal(t, expected, ids)
+		assert.Equal(t, i < len(pages)-1, slice.HasMore)
+		assert.Equal(t, i < len(pages)-1, slice.NextCursor != "")
+		assert.Equal(t, i > 0, slice.PrevCursor != "")
+		assert.False(t, slice.Counted())
+
+		cursor = slice.NextCursor
+		slices = append(slices, slice)
+	}
+
+	// backward from the last page
+	ids, slice := keysetPage(t, h, sort, slices[2].PrevCursor)
+	assert.Equal(t, pages[1], ids)
+	assert.True(t, slice.HasMore)
+	assert.NotEmpty(t, slice.PrevCursor)
+	assert.NotEmpty(t, slice.NextCursor)
+
+	ids, slice = keysetPage(t, h, sort, slice.PrevCursor)
+	assert.Equal(t, pages[0], ids)
+	assert.False(t, slice.HasMore)
+	assert.Empty(t, slice.PrevCursor)
+
+	ids, _ = keysetPage(t, h, sort, slice.NextCursor)
+	assert.Equal(t, pages[1], ids)
+
+	// primary key order without sort
+	ids, slice = keysetPage(t, h, nil, "")
+	assert.Equal(t, []int{1, 2, 3, 4}, ids)
+	ids, _ = keysetPage(t, h, nil, slice.NextCursor)
+	assert.Equal(t, []int{5, 6, 7, 8}, ids)
+
+	tests := []struct {
+		sort     []Sort
+		cursor   *Cursor
+		expected error
+	}{
+		{nil, &Cursor{Fields: []string{"Views", "ID"}, Values: []json.RawMessage{[]byte("1"), []byte("1")}}, NewInvalidCursorErr("sort does not match")},
+		{sort, &Cursor{Fields: []string{"Title", "ID"}, Values: []json.RawMessage{[]byte("1"), []byte("1")}}, NewInvalidCursorErr("sort does not match")},
+		{sort, &Cursor{Fields: []string{"Views", "ID"}, Values: []json.RawMessage{[]byte(`"a"`), []byte("1")}}, NewInvalidCursorErr("malformed value")},
+	}
+
+	for _, test := range tests {
+		q := NewCollectionQuery(&[]streamedThing{})
+		q.SetSlice(&Slice{Keyset: true, Limit: 4, Sort: test.sort, Cursor: test.cursor})
+		h.Handle(q)
+		assert.Equal(t, test.expected, q.Error())
+	}
+
+	// rows without rank could not be paged to
+	q := NewCollectionQuery(&[]rankedThing{})
+	q.SetSlice(&Slice{Keyset: true, Limit: 4, Sort: []Sort{{"Rank", ASC}}})
+	h.Handle(q)
+	assert.Equal(t, NewNullableKeysetFieldErr("Rank"), q.Error())
+}
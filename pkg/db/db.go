@@ -1,32 +1,77 @@
 package db
 
 import (
-	"fmt"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-func NewDatabase(c Config) *gorm.DB {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Europe/Berlin",
-		c.Host,
-		c.User,
-		c.Password,
-		c.DatabaseName,
-		c.Port,
-	)
+const (
+	defaultRetryBackoff = time.Second
+	maxRetryBackoff     = 30 * time.Second
+)
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{DisableForeignKeyConstraintWhenMigrating: true,
-		Logger: logger.Default.LogMode(parseLogLevel(c.Logging))})
+// NewDatabase opens the database of the configured driver, retrying while it is not reachable yet
+func NewDatabase(c Config) (*gorm.DB, error) {
+	dialector, err := Dialector(c)
+	if err != nil {
+		return nil, err
+	}
 
+	db, err := open(dialector, c)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	if err = configurePool(db, c); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func open(dialector gorm.Dialector, c Config) (db *gorm.DB, err error) {
+	backoff := c.RetryBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		db, err = gorm.Open(dialector, &gorm.Config{DisableForeignKeyConstraintWhenMigrating: true,
+			Logger: logger.Default.LogMode(parseLogLevel(c.Logging))})
+		if err == nil || attempt >= c.ConnectRetries {
+			return
+		}
+
+		log.WithError(err).Warnf("connecting to database failed, retrying in %s", backoff)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+func configurePool(db *gorm.DB, c Config) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	if c.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+	if c.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(c.ConnMaxIdleTime)
 	}
 
-	return db
+	return nil
 }
 
 func parseLogLevel(logLevel string) logger.LogLevel {
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDatabase(t *testing.T) {
+	db, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: ":memory:", MaxOpenConns: 3, ConnMaxIdleTime: time.Minute})
+	assert.Nil(t, err)
+
+	sqlDB, err := db.DB()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, sqlDB.Stats().MaxOpenConnections)
+
+	_, err = NewDatabase(Config{Driver: Sqlite, DatabaseName: "/nonexistent/dir/db.sqlite", ConnectRetries: 2, RetryBackoff: time.Millisecond})
+	assert.NotNil(t, err)
+
+	_, err = NewDatabase(Config{Driver: "oracle"})
+	assert.Equal(t, NewUnknownDriverErr("oracle"), err)
+}
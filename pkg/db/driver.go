@@ -0,0 +1,165 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	gormMysql "gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	Postgres = "postgres"
+	Sqlite   = "sqlite"
+	Mysql    = "mysql"
+)
+
+const (
+	defaultSSLMode  = "disable"
+	defaultTimeZone = "Europe/Berlin"
+)
+
+// Driver opens the dialector of a database driver for the config
+type Driver func(c Config) (gorm.Dialector, error)
+
+var drivers = map[string]Driver{
+	Postgres: postgresDriver,
+	Sqlite:   sqliteDriver,
+	Mysql:    mysqlDriver,
+}
+
+type UnknownDriverErr struct {
+	name string
+}
+
+func (e UnknownDriverErr) Error() string {
+	return fmt.Sprintf("unknown database driver %s", e.name)
+}
+
+func NewUnknownDriverErr(name string) UnknownDriverErr {
+	return UnknownDriverErr{name}
+}
+
+// RegisterDriver adds or replaces the driver selected by Config.Driver name
+func RegisterDriver(name string, driver Driver) {
+	drivers[strings.ToLower(name)] = driver
+}
+
+// Dialector of the driver selected by the config
+func Dialector(c Config) (gorm.Dialector, error) {
+	name := strings.ToLower(c.Driver)
+	if name == "" {
+		name = Postgres
+	}
+
+	driver, ok := drivers[name]
+	if !ok {
+		return nil, NewUnknownDriverErr(c.Driver)
+	}
+
+	return driver(c)
+}
+
+func postgresDriver(c Config) (gorm.Dialector, error) {
+	if c.DSN != "" {
+		return postgres.Open(c.DSN), nil
+	}
+
+	return postgres.Open(PostgresDSN(c)), nil
+}
+
+// PostgresDSN
+// host=localhost user=api password=secret dbname=api port=5432 sslmode=disable TimeZone=Europe/Berlin
+func PostgresDSN(c Config) string {
+	options := [][]string{
+		{"host", c.Host},
+		{"user", c.User},
+		{"password", c.Password},
+		{"dbname", c.DatabaseName},
+		{"port", c.Port},
+		{"sslmode", withDefault(c.SSLMode, defaultSSLMode)},
+		{"TimeZone", withDefault(c.TimeZone, defaultTimeZone)},
+		{"search_path", c.SearchPath},
+		{"application_name", c.ApplicationName},
+	}
+
+	pairs := make([]string, 0, len(options))
+	for _, option := range options {
+		if option[1] != "" {
+			pairs = append(pairs, option[0]+"="+quotePostgresValue(option[1]))
+		}
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// quotePostgresValue quotes values containing spaces or quotes, e.g. pass word => 'pass word'
+func quotePostgresValue(value string) string {
+	if !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// sqliteDriver DatabaseName is the path of the database file, e.g. :memory: or file::memory:?cache=shared
+func sqliteDriver(c Config) (gorm.Dialector, error) {
+	if c.DSN != "" {
+		return sqlite.Open(c.DSN), nil
+	}
+
+	return sqlite.Open(c.DatabaseName), nil
+}
+
+func mysqlDriver(c Config) (gorm.Dialector, error) {
+	if c.DSN != "" {
+		return gormMysql.Open(c.DSN), nil
+	}
+
+	dsn, err := MysqlDSN(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return gormMysql.Open(dsn), nil
+}
+
+// MysqlDSN
+// api:secret@tcp(localhost:3306)/api?charset=utf8mb4&parseTime=true&loc=Europe%2FBerlin
+// SSLMode is passed as tls option, search path and application name are not supported
+func MysqlDSN(c Config) (string, error) {
+	loc, err := time.LoadLocation(withDefault(c.TimeZone, defaultTimeZone))
+	if err != nil {
+		return "", err
+	}
+
+	mc := mysql.NewConfig()
+	mc.User = c.User
+	mc.Passwd = c.Password
+	mc.Net = "tcp"
+	mc.Addr = c.Host
+	if c.Port != "" {
+		mc.Addr = c.Host + ":" + c.Port
+	}
+	mc.DBName = c.DatabaseName
+	mc.ParseTime = true
+	mc.Loc = loc
+	mc.Params = map[string]string{"charset": "utf8mb4"}
+	if c.SSLMode != "" {
+		mc.TLSConfig = c.SSLMode
+	}
+
+	return mc.FormatDSN(), nil
+}
+
+func withDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
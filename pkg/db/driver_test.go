@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresDSN(t *testing.T) {
+	tests := []struct {
+		config   Config
+		expected string
+	}{
+		{
+			Config{Host: "localhost", User: "api", Password: "secret", DatabaseName: "api", Port: "5432"},
+			"host=localhost user=api password=secret dbname=api port=5432 sslmode=disable TimeZone=Europe/Berlin",
+		},
+		{
+			Config{Host: "db", Password: "it's secret", SSLMode: "require", TimeZone: "UTC", SearchPath: "app,public", ApplicationName: "my api"},
+			`host=db password='it\'s secret' sslmode=require TimeZone=UTC search_path=app,public application_name='my api'`,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, PostgresDSN(test.config))
+	}
+}
+
+func TestMysqlDSN(t *testing.T) {
+	dsn, err := MysqlDSN(Config{Host: "localhost", Port: "3306", User: "api", Password: "secret", DatabaseName: "api", SSLMode: "skip-verify"})
+	assert.Nil(t, err)
+	assert.Equal(t, "api:secret@tcp(localhost:3306)/api?loc=Europe%2FBerlin&parseTime=true&tls=skip-verify&charset=utf8mb4", dsn)
+
+	_, err = MysqlDSN(Config{TimeZone: "Nowhere/Invalid"})
+	assert.NotNil(t, err)
+}
+
+func TestDialector(t *testing.T) {
+	dialector, err := Dialector(Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, Postgres, dialector.Name())
+
+	dialector, err = Dialector(Config{Driver: "SQLite", DatabaseName: ":memory:"})
+	assert.Nil(t, err)
+	assert.Equal(t, Sqlite, dialector.Name())
+
+	_, err = Dialector(Config{Driver: "oracle"})
+	assert.Equal(t, NewUnknownDriverErr("oracle"), err)
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"gorm.io/gorm"
 )
@@ -10,12 +12,19 @@ type Repository interface {
 	Supports(t interface{}) bool
 }
 
+// TransactionalRepository repositories bound to the transaction of QueryManager.Transaction by WithDB
+// repositories not implementing it keep running outside the transaction
+type TransactionalRepository interface {
+	Repository
+	WithDB(tx *gorm.DB) Repository
+}
+
 type RepositoryNotFoundErr struct {
 	t interface{}
 }
 
 func (e RepositoryNotFoundErr) Error() string {
-	return fmt.Sprintf("could not find repository for type %t", e.t)
+	return fmt.Sprintf("could not find repository for type %T", e.t)
 }
 
 func NewRepositoryNotFound(t interface{}) RepositoryNotFoundErr {
@@ -67,6 +76,47 @@ func (m *QueryManager) Get(t interface{}) (Repository, error) {
 
 	return nil, NewRepositoryNotFound(t)
 }
+
+// Handle passes the query to the repository supporting its model
+func (m *QueryManager) Handle(q QueryObject) QueryObject {
+	r, err := m.Get(q.Model())
+	if err != nil {
+		q.SetError(err)
+		return q
+	}
+
+	return r.Handle(q)
+}
+
+// Transaction runs fn with a manager whose repositories are bound to a transaction
+// the transaction is committed if fn returns nil and rolled back otherwise, nested transactions use savepoints
+func (m *QueryManager) Transaction(ctx context.Context, fn func(tm *QueryManager) error, opts ...*sql.TxOptions) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(m.withDB(tx))
+	}, opts...)
+}
+
+func (m *QueryManager) withDB(db *gorm.DB) *QueryManager {
+	tm := &QueryManager{
+		defaultRepository: bindRepository(m.defaultRepository, db),
+		repositories:      make([]Repository, 0, len(m.repositories)),
+		db:                db,
+	}
+	for _, r := range m.repositories {
+		tm.repositories = append(tm.repositories, bindRepository(r, db))
+	}
+
+	return tm
+}
+
+func bindRepository(r Repository, db *gorm.DB) Repository {
+	if tr, ok := r.(TransactionalRepository); ok {
+		return tr.WithDB(db)
+	}
+
+	return r
+}
+
 func (m *QueryManager) Default() (Repository, error) {
 	if m.defaultRepository == nil {
 		return nil, NewDefaultRepositoryNotSetErr()
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type plainRepository struct{}
+
+func (plainRepository) Handle(q QueryObject) QueryObject {
+	return q
+}
+
+func (plainRepository) Supports(t interface{}) bool {
+	_, ok := t.(*[]string)
+	return ok
+}
+
+func newTransactionManager(t *testing.T) (*QueryManager, *gorm.DB) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "manager.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+
+	return NewQueryManager(conn), conn
+}
+
+func thingTitles(t *testing.T, conn *gorm.DB) []string {
+	things := make([]streamedThing, 0)
+	assert.Nil(t, conn.Order("id").Find(&things).Error)
+
+	titles := make([]string, 0)
+	for _, thing := range things {
+		titles = append(titles, thing.Title)
+	}
+
+	return titles
+}
+
+func TestQueryManager_Transaction(t *testing.T) {
+	failed := errors.New("failed")
+	create := func(tm *QueryManager, title string) error {
+		return tm.Handle(NewCreateQuery(&streamedThing{Title: title})).Error()
+	}
+
+	tests := []struct {
+		name     string
+		fn       func(tm *QueryManager) error
+		err      error
+		expected []string
+	}{
+		{"commit", func(tm *QueryManager) error {
+			return create(tm, "foo")
+		}, nil, []string{"foo"}},
+		{"rollback on error", func(tm *QueryManager) error {
+			assert.Nil(t, create(tm, "foo"))
+			return failed
+		}, failed, []string{}},
+		{"nested rollback to savepoint", func(tm *QueryManager) error {
+			assert.Nil(t, create(tm, "foo"))
+			err := tm.Transaction(context.Background(), func(nested *QueryManager) error {
+				assert.Nil(t, create(nested, "bar"))
+				return failed
+			})
+			assert.Equal(t, failed, err)
+			return create(tm, "baz")
+		}, nil, []string{"foo", "baz"}},
+		{"nested commit", func(tm *QueryManager) error {
+			assert.Nil(t, tm.Transaction(context.Background(), func(nested *QueryManager) error {
+				return create(nested, "bar")
+			}))
+			return nil
+		}, nil, []string{"bar"}},
+	}
+
+	for _, test := range tests {
+		m, conn := newTransactionManager(t)
+
+		err := m.Transaction(context.Background(), test.fn)
+		assert.Equal(t, test.err, err, test.name)
+		assert.Equal(t, test.expected, thingTitles(t, conn), test.name)
+	}
+}
+
+func TestQueryManager_TransactionPanic(t *testing.T) {
+	m, conn := newTransactionManager(t)
+
+	assert.PanicsWithValue(t, "failed", func() {
+		_ = m.Transaction(context.Background(), func(tm *QueryManager) error {
+			assert.Nil(t, tm.Handle(NewCreateQuery(&streamedThing{Title: "foo"})).Error())
+			panic("failed")
+		})
+	})
+	assert.Equal(t, []string{}, thingTitles(t, conn))
+}
+
+func TestQueryManager_TransactionRepositories(t *testing.T) {
+	m, conn := newTransactionManager(t)
+	plain := plainRepository{}
+	m.Register(plain)
+	m.Register(NewQueryHandler(conn))
+
+	assert.Nil(t, m.Transaction(context.Background(), func(tm *QueryManager) error {
+		assert.NotSame(t, conn, tm.DB())
+
+		// repositories not implementing TransactionalRepository are kept
+		r, err := tm.Get(&[]string{})
+		assert.Nil(t, err)
+		assert.Equal(t, plain, r)
+
+		r, err = tm.Get(&streamedThing{})
+		assert.Nil(t, err)
+		assert.Same(t, tm.DB(), r.(*QueryHandler).db)
+
+		return nil
+	}))
+}
@@ -1,11 +1,12 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"gorm.io/gorm/clause"
 	"reflect"
-	"sync"
+	"strings"
 
 	"gorm.io/gorm"
 	gormSchema "gorm.io/gorm/schema"
@@ -29,13 +30,28 @@ func NewQueryHandler(db *gorm.DB) *QueryHandler {
 	}
 }
 
+// WithDB handler running its queries on db, e.g. a transaction
+func (h *QueryHandler) WithDB(db *gorm.DB) Repository {
+	return NewQueryHandler(db)
+}
+
 func (h *QueryHandler) Supports(t interface{}) bool {
 	h.db.Model(t)
 	return true
 }
 
 func (h *QueryHandler) Handle(query QueryObject) QueryObject {
-	stmt, schema, err := h.buildStatement(query.Model())
+	if wq, ok := query.(WriteQueryObject); ok {
+		return h.handleWrite(wq)
+	}
+	if sq, ok := query.(StreamedQueryObject); ok && sq.Stream() {
+		return h.handleStream(sq)
+	}
+
+	ctx, cancel := queryContext(query)
+	defer cancel()
+
+	stmt, schema, err := h.buildStatement(ctx, query.Model())
 	if err != nil {
 		query.SetError(err)
 		return query
@@ -54,21 +70,58 @@ func (h *QueryHandler) Handle(query QueryObject) QueryObject {
 	case QueryObject:
 		buildPreloads(stmt, query.Preloads())
 	}
+	buildFields(stmt, query, schema)
+
+	iq, identified := query.(IdentifiedQueryObject)
+	identified = identified && iq.ID() != nil
+	if identified {
+		condition, err := primaryKeyCondition(schema, iq.ID())
+		if err != nil {
+			query.SetError(err)
+			return query
+		}
+		stmt.Where(condition)
+	}
 
 	result = h.buildResult(query.Model())
 	if query.Error() != nil {
 		return query
 	}
 
-	stmt.Find(result)
+	res := stmt.Find(result)
+	if res.Error != nil {
+		query.SetError(res.Error)
+		return query
+	}
+	if identified && res.RowsAffected == 0 {
+		query.SetError(NewEntityNotFoundErr(schema.Name, iq.ID()))
+		return query
+	}
+
+	if sq, ok := query.(SlicedQueryObject); ok && sq.Slice() != nil {
+		if err = finishSelection(sq, result, schema); err != nil {
+			query.SetError(err)
+			return query
+		}
+	}
 	query.SetResult(result)
 
 	return query
 }
 
-func (h *QueryHandler) buildStatement(result interface{}) (stmt *gorm.DB, schema *gormSchema.Schema, err error) {
-	stmt = h.db.Model(result)
-	schema, err = gormSchema.Parse(result, &sync.Map{}, gormSchema.NamingStrategy{})
+// queryContext context of the query limited by its timeout
+func queryContext(query QueryObject) (context.Context, context.CancelFunc) {
+	if query.Timeout() > 0 {
+		return context.WithTimeout(query.Context(), query.Timeout())
+	}
+
+	return query.Context(), func() {}
+}
+
+func (h *QueryHandler) buildStatement(ctx context.Context, result interface{}) (stmt *gorm.DB, schema *gormSchema.Schema, err error) {
+	stmt = h.db.WithContext(ctx).Model(result)
+	err = stmt.Statement.Parse(result)
+	schema = stmt.Statement.Schema
 
 	return
 }
@@ -89,22 +142,90 @@ func buildPreloads(stmt *gorm.DB, pre []Preload) {
 	}
 }
 
+// buildFields selects the requested fields only, primary keys and foreign keys of preloaded relations are always selected
+func buildFields(stmt *gorm.DB, query QueryObject, schema *gormSchema.Schema) {
+	if len(query.Fields()) == 0 {
+		return
+	}
+
+	columns := append([]string{}, schema.PrimaryFieldDBNames...)
+	for _, name := range query.Fields() {
+		field := schema.FieldsByName[name]
+		if field == nil {
+			query.SetError(fmt.Errorf("unknown field name %s", name))
+			return
+		}
+
+		// associations have no column of their own
+		if field.DBName != "" {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	// keyset pagination reads the cursor values from the rows
+	if sq, ok := query.(SlicedQueryObject); ok && sq.Slice() != nil && sq.Slice().Keyset {
+		for _, sort := range keysetSort(sq.Slice().Sort, schema) {
+			if field := schema.FieldsByName[sort.FieldName]; field != nil {
+				columns = append(columns, field.DBName)
+			}
+		}
+	}
+
+	for _, preload := range query.Preloads() {
+		name, _, _ := strings.Cut(preload.Name, pathSeparator)
+		rel, ok := schema.Relationships.Relations[name]
+		if !ok || rel.JoinTable != nil {
+			continue
+		}
+
+		for _, ref := range rel.References {
+			if !ref.OwnPrimaryKey && ref.PrimaryValue == "" {
+				columns = append(columns, ref.ForeignKey.DBName)
+			}
+		}
+	}
+
+	stmt.Select(uniqueStrings(columns))
+}
+
+func uniqueStrings(values []string) []string {
+	unique := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+
+	return unique
+}
+
 func buildSelection(stmt *gorm.DB, query SlicedQueryObject, schema *gormSchema.Schema) {
 	sel := query.Slice()
 	if sel == nil {
 		return
 	}
 
-	var total int64
+	// keyset pagination neither counts nor skips rows
+	if sel.Keyset {
+		if err := buildKeyset(stmt, sel, schema); err != nil {
+			query.SetError(err)
+		}
+		return
+	}
 
-	stmt.Session(&gorm.Session{})
-	stmt.Count(&total)
-	sel.Total = total
+	if err := buildCount(stmt, sel); err != nil {
+		query.SetError(err)
+		return
+	}
 
 	if sel.Offset > 0 {
 		stmt.Offset(sel.Offset)
 	}
-	if sel.Limit > 0 {
+	if sel.probes() {
+		stmt.Limit(sel.Limit + 1)
+	} else if sel.Limit > 0 {
 		stmt.Limit(sel.Limit)
 	}
 	if len(sel.Sort) > 0 {
@@ -117,26 +238,108 @@ func buildSelection(stmt *gorm.DB, query SlicedQueryObject, schema *gormSchema.S
 	return
 }
 
+// finishSelection trims the probe row of slices not knowing their total
+func finishSelection(query SlicedQueryObject, result interface{}, schema *gormSchema.Schema) error {
+	sel := query.Slice()
+	if sel.Keyset {
+		return finishKeyset(query, result, schema)
+	}
+	if !sel.probes() {
+		return nil
+	}
+
+	rows := reflect.ValueOf(result).Elem()
+	sel.HasMore = rows.Len() > sel.Limit
+	if sel.HasMore {
+		rows.Set(rows.Slice(0, sel.Limit))
+	}
+
+	return nil
+}
+
 func buildFilter(stmt *gorm.DB, query FilteredQueryObject, schema *gormSchema.Schema) {
-	filters := query.Filters()
-	if len(filters) == 0 {
-		return
+	for _, filter := range query.Filters() {
+		condition, args, err := buildCondition(filter, schema)
+		if err != nil {
+			query.SetError(err)
+			return
+		}
+
+		stmt.Where(condition, args...)
 	}
 
-	fields := schema.FieldsByName
-	for _, filter := range filters {
-		field := fields[filter.FieldName]
-		if field == nil {
-			query.SetError(errors.New("unknown field name"))
+	for _, group := range query.FilterGroups() {
+		condition, args, err := buildGroupCondition(group, schema)
+		if err != nil {
+			query.SetError(err)
 			return
 		}
+		if condition == "" {
+			continue
+		}
 
-		stmt.Where(fmt.Sprintf("%s %s ?", field.DBName, filter.Operator), filter.Value)
+		stmt.Where(condition, args...)
 	}
 
 	return
 }
 
+// buildCondition field names may be paths over associations, e.g. Author.Name
+func buildCondition(filter Filter, schema *gormSchema.Schema) (string, []interface{}, error) {
+	return buildPathCondition(filter, strings.Split(filter.FieldName, pathSeparator), schema, "", 1)
+}
+
+func renderCondition(filter Filter, column clause.Column) (string, []interface{}, error) {
+	if fn, ok := conditions[filter.Operator]; ok {
+		return fn(column, filter.Value)
+	}
+
+	return fmt.Sprintf("? %s ?", filter.Operator), []interface{}{column, filter.Value}, nil
+}
+
+// buildGroupCondition
+// FilterGroup{Conjunction: OR, Filters: {a = 1, b = 2}, Groups: {{Conjunction: NOT, Filters: {c = 3}}}} => (a = ? OR b = ? OR NOT (c = ?))
+func buildGroupCondition(group FilterGroup, schema *gormSchema.Schema) (string, []interface{}, error) {
+	conditions := make([]string, 0, len(group.Filters)+len(group.Groups))
+	args := make([]interface{}, 0)
+
+	for _, filter := range group.Filters {
+		condition, filterArgs, err := buildCondition(filter, schema)
+		if err != nil {
+			return "", nil, err
+		}
+
+		conditions = append(conditions, condition)
+		args = append(args, filterArgs...)
+	}
+
+	for _, nested := range group.Groups {
+		condition, nestedArgs, err := buildGroupCondition(nested, schema)
+		if err != nil {
+			return "", nil, err
+		}
+		if condition == "" {
+			continue
+		}
+
+		conditions = append(conditions, condition)
+		args = append(args, nestedArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return "", args, nil
+	}
+
+	switch group.Conjunction {
+	case OR:
+		return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+	case NOT:
+		return "NOT (" + strings.Join(conditions, " AND ") + ")", args, nil
+	default:
+		return "(" + strings.Join(conditions, " AND ") + ")", args, nil
+	}
+}
+
 func buildSort(stmt *gorm.DB, sorts []Sort, schema *gormSchema.Schema) error {
 	fields := schema.FieldsByName
 	for _, sort := range sorts {
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryHandler_HandleFilterGroups(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "groups.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	for i := 1; i <= 10; i++ {
+		assert.Nil(t, conn.Create(&streamedThing{Title: fmt.Sprintf("t%d", i), Views: i % 3}).Error)
+	}
+
+	tests := []struct {
+		filters  []Filter
+		groups   []FilterGroup
+		expected []int
+	}{
+		// views = 2 OR NOT (id > 3)
+		{nil, []FilterGroup{{Conjunction: OR, Filters: []Filter{{"Views", "=", 2}}, Groups: []FilterGroup{
+			{Conjunction: NOT, Filters: []Filter{{"ID", ">", 3}}},
+		}}}, []int{1, 2, 3, 5, 8}},
+		// NOT combines its members by AND: id = 10 OR NOT (views = 1 AND id < 8)
+		{nil, []FilterGroup{{Conjunction: OR, Filters: []Filter{{"ID", "=", 10}}, Groups: []FilterGroup{
+			{Conjunction: NOT, Filters: []Filter{{"Views", "=", 1}, {"ID", "<", 8}}},
+		}}}, []int{2, 3, 5, 6, 8, 9, 10}},
+		// views <> 0 AND (id < 3 OR NOT (id < 9 OR id = 10))
+		{[]Filter{{"Views", "<>", 0}}, []FilterGroup{{Conjunction: OR, Filters: []Filter{{"ID", "<", 3}}, Groups: []FilterGroup{
+			{Conjunction: NOT, Groups: []FilterGroup{{Conjunction: OR, Filters: []Filter{{"ID", "<", 9}, {"ID", "=", 10}}}}},
+		}}}, []int{1, 2}},
+		// empty groups add no condition
+		{nil, []FilterGroup{{Conjunction: OR, Groups: []FilterGroup{{Conjunction: NOT}}}}, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+	}
+
+	h := NewQueryHandler(conn)
+	for _, test := range tests {
+		q := NewCollectionQuery(&[]streamedThing{})
+		q.SetSlice(&Slice{Sort: []Sort{{"ID", ASC}}})
+		q.SetFilters(test.filters)
+		q.SetFilterGroups(test.groups)
+
+		h.Handle(q)
+		assert.Nil(t, q.Error())
+
+		ids := make([]int, 0)
+		for _, thing := range *q.Result().(*[]streamedThing) {
+			ids = append(ids, thing.ID)
+		}
+		assert.Equal(t, test.expected, ids)
+	}
+}
@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	gormSchema "gorm.io/gorm/schema"
+)
+
+type UnsupportedStreamPreloadErr struct {
+}
+
+func (e UnsupportedStreamPreloadErr) Error() string {
+	return "preloads are not supported by streamed queries"
+}
+
+func NewUnsupportedStreamPreloadErr() UnsupportedStreamPreloadErr {
+	return UnsupportedStreamPreloadErr{}
+}
+
+// handleStream sets an iterator over the rows instead of the result, the query is cancelled when it is closed
+func (h *QueryHandler) handleStream(query StreamedQueryObject) QueryObject {
+	if len(query.Preloads()) > 0 {
+		query.SetError(NewUnsupportedStreamPreloadErr())
+		return query
+	}
+
+	elemType := reflect.TypeOf(h.buildResult(query.Model())).Elem()
+	if elemType.Kind() != reflect.Slice {
+		query.SetError(fmt.Errorf("streamed model must be a slice, got %s", elemType))
+		return query
+	}
+	elemType = elemType.Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	// the timeout applies to opening the rows only, iterating them is bound to the context of the query
+	ctx, cancel := context.WithCancel(query.Context())
+	stmt, schema, err := h.buildStatement(ctx, query.Model())
+	if err != nil {
+		cancel()
+		query.SetError(err)
+		return query
+	}
+
+	buildFilter(stmt, query, schema)
+	buildStream(stmt, query, schema)
+	buildFields(stmt, query, schema)
+	if query.Error() != nil {
+		cancel()
+		return query
+	}
+
+	rows, err := openRows(stmt, query.Timeout(), cancel)
+	if err != nil {
+		cancel()
+		query.SetError(err)
+		return query
+	}
+
+	query.SetIterator(&rowsIterator{
+		stmt:     stmt,
+		rows:     rows,
+		cancel:   cancel,
+		elemType: elemType,
+	})
+
+	return query
+}
+
+// openRows cancels the statement if the rows are not opened within the timeout, no timeout if 0
+func openRows(stmt *gorm.DB, timeout time.Duration, cancel context.CancelFunc) (*sql.Rows, error) {
+	if timeout <= 0 {
+		return stmt.Rows()
+	}
+
+	timer := time.AfterFunc(timeout, cancel)
+	rows, err := stmt.Rows()
+	if !timer.Stop() {
+		if rows != nil {
+			rows.Close()
+		}
+		return nil, fmt.Errorf("opening rows: %w", context.DeadlineExceeded)
+	}
+
+	return rows, err
+}
+
+// buildStream applies sort, cursor, offset and limit of the slice without counting or probing
+// backward cursors stream in inverted order
+func buildStream(stmt *gorm.DB, query StreamedQueryObject, schema *gormSchema.Schema) {
+	sel := query.Slice()
+	if sel == nil {
+		return
+	}
+
+	if sel.Keyset {
+		keyset := *sel
+		keyset.Limit = 0
+		if err := buildKeyset(stmt, &keyset, schema); err != nil {
+			query.SetError(err)
+			return
+		}
+	} else {
+		if sel.Offset > 0 {
+			stmt.Offset(sel.Offset)
+		}
+		if err := buildSort(stmt, sel.Sort, schema); err != nil {
+			query.SetError(err)
+			return
+		}
+	}
+
+	if sel.Limit > 0 {
+		stmt.Limit(sel.Limit)
+	}
+}
+
+// rowsIterator scans the rows one at a time into new elements of the model
+type rowsIterator struct {
+	stmt     *gorm.DB
+	rows     *sql.Rows
+	cancel   context.CancelFunc
+	elemType reflect.Type
+	value    interface{}
+	err      error
+}
+
+func (it *rowsIterator) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+
+	value := reflect.New(it.elemType).Interface()
+	if err := it.stmt.ScanRows(it.rows, value); err != nil {
+		it.err = err
+		return false
+	}
+	it.value = value
+
+	return true
+}
+
+func (it *rowsIterator) Value() interface{} {
+	return it.value
+}
+
+func (it *rowsIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+
+	return it.rows.Err()
+}
+
+func (it *rowsIterator) Close() error {
+	defer it.cancel()
+
+	return it.rows.Close()
+}
+
+// SliceIterator iterates rows already in memory, e.g. of repositories not backed by gorm
+type SliceIterator struct {
+	rows  reflect.Value
+	index int
+}
+
+// NewSliceIterator rows is a slice or pointer to a slice, values are pointers to its elements, nil rows are empty
+func NewSliceIterator(rows interface{}) *SliceIterator {
+	return &SliceIterator{rows: reflect.Indirect(reflect.ValueOf(rows)), index: -1}
+}
+
+func (it *SliceIterator) Next() bool {
+	if !it.rows.IsValid() || it.index+1 >= it.rows.Len() {
+		return false
+	}
+	it.index++
+
+	return true
+}
+
+func (it *SliceIterator) Value() interface{} {
+	if !it.rows.IsValid() || it.index < 0 || it.index >= it.rows.Len() {
+		return nil
+	}
+
+	return it.rows.Index(it.index).Addr().Interface()
+}
+
+func (it *SliceIterator) Err() error {
+	return nil
+}
+
+func (it *SliceIterator) Close() error {
+	return nil
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type streamedThing struct {
+	ID    int
+	Title string
+	Views int
+}
+
+func TestQueryHandler_HandleStream(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "stream.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	for i := 1; i <= 10; i++ {
+		assert.Nil(t, conn.Create(&streamedThing{Title: fmt.Sprintf("t%d", i), Views: i % 3}).Error)
+	}
+	after := &Cursor{Fields: []string{"ID"}, Values: []json.RawMessage{[]byte("7")}}
+
+	tests := []struct {
+		slice    *Slice
+		filters  []Filter
+		fields   []string
+		expected []int
+	}{
+		{&Slice{}, nil, nil, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{&Slice{Offset: 2, Limit: 3, Count: CountExact}, nil, nil, []int{3, 4, 5}},
+		{&Slice{Sort: []Sort{{"Views", DESC}, {"ID", ASC}}, Limit: 4}, nil, []string{"Views"}, []int{2, 5, 8, 1}},
+		{&Slice{}, []Filter{{"Views", "=", 0}}, nil, []int{3, 6, 9}},
+		{&Slice{Keyset: true, Cursor: after, Limit: 2}, nil, nil, []int{8, 9}},
+	}
+
+	h := NewQueryHandler(conn)
+	for _, test := range tests {
+		q := NewCollectionQuery(&[]streamedThing{})
+		q.SetSlice(test.slice)
+		q.SetFilters(test.filters)
+		q.SetFields(test.fields)
+		q.SetStream(true)
+
+		h.Handle(q)
+		assert.Nil(t, q.Error())
+		assert.Nil(t, q.Result())
+
+		ids := make([]int, 0)
+		it := q.Iterator()
+		for it.Next() {
+			thing := it.Value().(*streamedThing)
+			ids = append(ids, thing.ID)
+			if len(test.fields) > 0 {
+				assert.Empty(t, thing.Title)
+			}
+		}
+		assert.Nil(t, it.Err())
+		assert.Nil(t, it.Close())
+		assert.Equal(t, test.expected, ids)
+	}
+
+	q := NewCollectionQuery(&[]streamedThing{})
+	q.SetStream(true)
+	q.AddPreload(Preload{Name: "Owner"})
+	h.Handle(q)
+	assert.Equal(t, NewUnsupportedStreamPreloadErr(), q.Error())
+
+	// rows opened in time are read beyond the timeout
+	q = NewCollectionQuery(&[]streamedThing{})
+	q.SetStream(true)
+	q.SetTimeout(20 * time.Millisecond)
+	h.Handle(q)
+	assert.Nil(t, q.Error())
+	time.Sleep(40 * time.Millisecond)
+	rows := 0
+	for it := q.Iterator(); it.Next(); rows++ {
+	}
+	assert.Nil(t, q.Iterator().Err())
+	assert.Nil(t, q.Iterator().Close())
+	assert.Equal(t, 10, rows)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q = NewCollectionQuery(&[]streamedThing{})
+	q.SetStream(true)
+	q.SetContext(ctx)
+	h.Handle(q)
+	assert.Nil(t, q.Error())
+	it := q.Iterator()
+	assert.True(t, it.Next())
+	cancel()
+	for it.Next() {
+	}
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+	assert.Nil(t, it.Close())
+}
+
+func TestSliceIterator(t *testing.T) {
+	it := NewSliceIterator(&[]streamedThing{{ID: 1}, {ID: 2}})
+	assert.Nil(t, it.Value())
+	assert.True(t, it.Next())
+	assert.Equal(t, &streamedThing{ID: 1}, it.Value())
+	assert.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+
+	assert.False(t, NewSliceIterator(nil).Next())
+}
@@ -1,8 +1,10 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"time"
 )
 
 type Direction string
@@ -12,6 +14,14 @@ const (
 	DESC Direction = "desc"
 )
 
+type Conjunction string
+
+const (
+	AND Conjunction = "AND"
+	OR  Conjunction = "OR"
+	NOT Conjunction = "NOT"
+)
+
 type QueryObject interface {
 	Model() interface{}
 	Error() error
@@ -19,11 +29,16 @@ type QueryObject interface {
 	Result() interface{}
 	SetResult(result interface{})
 	Preloads() []Preload
+	Fields() []string
+	Context() context.Context
+	SetContext(ctx context.Context)
+	Timeout() time.Duration
 }
 
 type FilteredQueryObject interface {
 	QueryObject
 	Filters() []Filter
+	FilterGroups() []FilterGroup
 }
 
 type SlicedQueryObject interface {
@@ -43,6 +58,9 @@ type Query struct {
 	result     interface{}
 	error      error
 	preloads   *[]Preload
+	fields     []string
+	ctx        context.Context
+	timeout    time.Duration
 }
 
 func NewQuery(model interface{}) *Query {
@@ -105,15 +123,54 @@ func (q *Query) SetPreloads(preloads *[]Preload) {
 	q.preloads = preloads
 }
 
+// Fields names of the fields to select, all fields are selected if empty
+func (q *Query) Fields() []string {
+	return q.fields
+}
+
+func (q *Query) SetFields(fields []string) {
+	q.fields = fields
+}
+
+// Context the query is cancelled with, defaults to context.Background
+func (q *Query) Context() context.Context {
+	if q.ctx == nil {
+		return context.Background()
+	}
+
+	return q.ctx
+}
+
+func (q *Query) SetContext(ctx context.Context) {
+	q.ctx = ctx
+}
+
+// Timeout the query is cancelled after, no timeout if 0
+func (q *Query) Timeout() time.Duration {
+	return q.timeout
+}
+
+func (q *Query) SetTimeout(timeout time.Duration) {
+	q.timeout = timeout
+}
+
 type Filter struct {
 	FieldName string
 	Operator  string
 	Value     interface{}
 }
 
+// FilterGroup combines its filters and nested groups by conjunction, NOT negates the AND combination of its members
+type FilterGroup struct {
+	Conjunction Conjunction
+	Filters     []Filter
+	Groups      []FilterGroup
+}
+
 type FilterQuery struct {
 	Query
 	filters []Filter
+	groups  []FilterGroup
 }
 
 func NewFilterQuery(model interface{}) *FilterQuery {
@@ -133,11 +190,51 @@ func (q *FilterQuery) SetFilters(filters []Filter) {
 	q.filters = filters
 }
 
+func (q *FilterQuery) FilterGroups() []FilterGroup {
+	return q.groups
+}
+
+func (q *FilterQuery) SetFilterGroups(groups []FilterGroup) {
+	q.groups = groups
+}
+
+type CountStrategy string
+
+const (
+	// CountExact counts all matching rows
+	CountExact CountStrategy = "exact"
+	// CountNone skips counting
+	CountNone CountStrategy = "none"
+	// CountCapped counts matching rows up to CountCap
+	CountCapped CountStrategy = "capped"
+	// CountEstimate uses the row estimate of the query planner, postgres only, other dialects count exactly
+	CountEstimate CountStrategy = "estimate"
+)
+
+// Slice offset/limit or, with Keyset set, cursor based page of a collection
 type Slice struct {
-	Offset int
-	Limit  int
-	Total  int64
-	Sort   []Sort
+	Offset     int
+	Limit      int
+	Total      int64
+	Sort       []Sort
+	Count      CountStrategy
+	CountCap   int64
+	Capped     bool
+	HasMore    bool
+	Keyset     bool
+	Cursor     *Cursor
+	NextCursor string
+	PrevCursor string
+}
+
+// Counted total is known exactly
+func (s *Slice) Counted() bool {
+	return !s.Keyset && (s.Count == "" || s.Count == CountExact || (s.Count == CountCapped && !s.Capped))
+}
+
+// probes fetches one row more than the limit to find out if there are more rows without knowing the total
+func (s *Slice) probes() bool {
+	return s.Limit > 0 && (s.Keyset || (s.Count != "" && s.Count != CountExact))
 }
 
 type Sort struct {
@@ -145,9 +242,30 @@ type Sort struct {
 	Direction Direction
 }
 
+// Iterator rows of a streamed query, Value is the current row as pointer to the element of the model, e.g. *Thing
+// Close releases the connection of the rows and has to be called when done
+type Iterator interface {
+	Next() bool
+	Value() interface{}
+	Err() error
+	Close() error
+}
+
+// StreamedQueryObject sliced queries handled with Stream set yield an Iterator instead of a result
+// streams are neither counted nor probed, a limit of 0 streams all rows
+type StreamedQueryObject interface {
+	SlicedQueryObject
+	Stream() bool
+	SetStream(flag bool)
+	Iterator() Iterator
+	SetIterator(it Iterator)
+}
+
 type CollectionQuery struct {
 	FilterQuery
-	slice *Slice
+	slice    *Slice
+	stream   bool
+	iterator Iterator
 }
 
 func NewCollectionQuery(model interface{}) *CollectionQuery {
@@ -166,3 +284,158 @@ func (q *CollectionQuery) Slice() *Slice {
 func (q *CollectionQuery) SetSlice(slice *Slice) {
 	q.slice = slice
 }
+
+func (q *CollectionQuery) Stream() bool {
+	return q.stream
+}
+
+func (q *CollectionQuery) SetStream(flag bool) {
+	q.stream = flag
+}
+
+// Iterator rows of the streamed query, nil until handled
+func (q *CollectionQuery) Iterator() Iterator {
+	return q.iterator
+}
+
+func (q *CollectionQuery) SetIterator(it Iterator) {
+	q.iterator = it
+}
+
+type Operation string
+
+const (
+	Create Operation = "create"
+	Update Operation = "update"
+	Patch  Operation = "patch"
+	Delete Operation = "delete"
+)
+
+// IdentifiedQueryObject restricts the query to the entity with the primary key ID, if not nil
+type IdentifiedQueryObject interface {
+	QueryObject
+	ID() interface{}
+}
+
+// EntityQuery reads the single entity with primary key id
+type EntityQuery struct {
+	FilterQuery
+	id interface{}
+}
+
+func NewEntityQuery(model interface{}, id interface{}) *EntityQuery {
+	return &EntityQuery{
+		FilterQuery: FilterQuery{
+			Query:   Query{model: model},
+			filters: make([]Filter, 0),
+		},
+		id: id,
+	}
+}
+
+func (q *EntityQuery) ID() interface{} {
+	return q.id
+}
+
+func (q *EntityQuery) SetID(id interface{}) {
+	q.id = id
+}
+
+// WriteQueryObject
+// Create inserts Model, Update writes all fields of Model, Patch writes Values only, Delete removes the matching rows
+// update, patch and delete require an ID or filters
+type WriteQueryObject interface {
+	FilteredQueryObject
+	ID() interface{}
+	Operation() Operation
+	Values() map[string]interface{}
+	Omitted() []string
+	RowsAffected() int64
+	SetRowsAffected(rows int64)
+}
+
+type WriteQuery struct {
+	FilterQuery
+	operation    Operation
+	id           interface{}
+	values       map[string]interface{}
+	omitted      []string
+	rowsAffected int64
+}
+
+func newWriteQuery(operation Operation, model interface{}) *WriteQuery {
+	return &WriteQuery{
+		FilterQuery: FilterQuery{
+			Query:   Query{model: model},
+			filters: make([]Filter, 0),
+		},
+		operation: operation,
+	}
+}
+
+// NewCreateQuery inserts model, a pointer to an entity or a slice of entities
+func NewCreateQuery(model interface{}) *WriteQuery {
+	return newWriteQuery(Create, model)
+}
+
+// NewUpdateQuery overwrites all fields but primary keys and creation times with the ones of model
+func NewUpdateQuery(model interface{}, id interface{}) *WriteQuery {
+	q := newWriteQuery(Update, model)
+	q.id = id
+
+	return q
+}
+
+// NewPatchQuery writes values only, keys are field or column names
+func NewPatchQuery(model interface{}, id interface{}, values map[string]interface{}) *WriteQuery {
+	q := newWriteQuery(Patch, model)
+	q.id = id
+	q.values = values
+
+	return q
+}
+
+// NewDeleteQuery deletes the entity with primary key id, or if id is nil all entities matching the filters
+func NewDeleteQuery(model interface{}, id interface{}) *WriteQuery {
+	q := newWriteQuery(Delete, model)
+	q.id = id
+
+	return q
+}
+
+func (q *WriteQuery) Operation() Operation {
+	return q.operation
+}
+
+func (q *WriteQuery) ID() interface{} {
+	return q.id
+}
+
+func (q *WriteQuery) SetID(id interface{}) {
+	q.id = id
+}
+
+func (q *WriteQuery) Values() map[string]interface{} {
+	return q.values
+}
+
+func (q *WriteQuery) SetValues(values map[string]interface{}) {
+	q.values = values
+}
+
+// Omitted field names not written by creates and updates, e.g. fields clients may not write
+func (q *WriteQuery) Omitted() []string {
+	return q.omitted
+}
+
+func (q *WriteQuery) SetOmitted(fields []string) {
+	q.omitted = fields
+}
+
+func (q *WriteQuery) RowsAffected() int64 {
+	return q.rowsAffected
+}
+
+func (q *WriteQuery) SetRowsAffected(rows int64) {
+	q.rowsAffected = rows
+}
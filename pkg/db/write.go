@@ -0,0 +1,277 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormSchema "gorm.io/gorm/schema"
+)
+
+type MissingConditionsErr struct {
+	operation Operation
+}
+
+func (e MissingConditionsErr) Error() string {
+	return fmt.Sprintf("refusing to %s without id or filters", e.operation)
+}
+
+func NewMissingConditionsErr(operation Operation) MissingConditionsErr {
+	return MissingConditionsErr{operation}
+}
+
+type EntityNotFoundErr struct {
+	name string
+	id   interface{}
+}
+
+func (e EntityNotFoundErr) Error() string {
+	return fmt.Sprintf("could not find %s with id %v", e.name, e.id)
+}
+
+func NewEntityNotFoundErr(name string, id interface{}) EntityNotFoundErr {
+	return EntityNotFoundErr{name, id}
+}
+
+// EntityIDConflictErr the primary key of the written entity differs from the id it is written to
+type EntityIDConflictErr struct {
+	name     string
+	id       interface{}
+	entityID interface{}
+}
+
+func (e EntityIDConflictErr) Error() string {
+	return fmt.Sprintf("%s with id %v cannot be written to id %v", e.name, e.entityID, e.id)
+}
+
+func NewEntityIDConflictErr(name string, id interface{}, entityID interface{}) EntityIDConflictErr {
+	return EntityIDConflictErr{name, id, entityID}
+}
+
+// handleWrite runs the write operation, the result is the written entity reloaded by id, or the created model
+func (h *QueryHandler) handleWrite(query WriteQueryObject) QueryObject {
+	ctx, cancel := queryContext(query)
+	defer cancel()
+
+	stmt, schema, err := h.buildStatement(ctx, query.Model())
+	if err != nil {
+		query.SetError(err)
+		return query
+	}
+
+	if query.Operation() == Create {
+		res := stmt.Omit(query.Omitted()...).Create(query.Model())
+		if res.Error != nil {
+			query.SetError(res.Error)
+			return query
+		}
+
+		query.SetRowsAffected(res.RowsAffected)
+		query.SetResult(query.Model())
+		return query
+	}
+
+	if err = buildWriteConditions(stmt, query, schema); err != nil {
+		query.SetError(err)
+		return query
+	}
+
+	var res *gorm.DB
+	switch query.Operation() {
+	case Update:
+		if err = checkEntityID(ctx, schema, query); err != nil {
+			query.SetError(err)
+			return query
+		}
+		res = stmt.Select("*").Omit(append(protectedFields(schema), query.Omitted()...)...).Updates(query.Model())
+	case Patch:
+		if len(query.Values()) == 0 {
+			// nothing to write, the entity is read as patched
+			return h.setReloaded(ctx, query, schema)
+		}
+		res = stmt.Updates(query.Values())
+	case Delete:
+		res = stmt.Delete(query.Model())
+	default:
+		query.SetError(UnsupportedQueryTypeErr{query})
+		return query
+	}
+
+	if res.Error != nil {
+		query.SetError(res.Error)
+		return query
+	}
+	query.SetRowsAffected(res.RowsAffected)
+
+	if query.ID() == nil {
+		return query
+	}
+	// no row matched the id and the filters, e.g. of another scope
+	if res.RowsAffected == 0 {
+		query.SetError(NewEntityNotFoundErr(schema.Name, query.ID()))
+		return query
+	}
+	if query.Operation() == Delete {
+		return query
+	}
+
+	return h.setReloaded(ctx, query, schema)
+}
+
+// setReloaded sets the written entity as result, writes without id have no result
+func (h *QueryHandler) setReloaded(ctx context.Context, query WriteQueryObject, schema *gormSchema.Schema) QueryObject {
+	if query.ID() == nil {
+		return query
+	}
+
+	result, err := h.reload(ctx, query, schema)
+	if err != nil {
+		query.SetError(err)
+		return query
+	}
+	query.SetResult(result)
+
+	return query
+}
+
+// buildWriteConditions restricts writes by id and filters, writes without any condition are refused
+func buildWriteConditions(stmt *gorm.DB, query WriteQueryObject, schema *gormSchema.Schema) error {
+	if query.ID() == nil && len(query.Filters()) == 0 && len(query.FilterGroups()) == 0 {
+		return NewMissingConditionsErr(query.Operation())
+	}
+
+	if query.ID() != nil {
+		condition, err := primaryKeyCondition(schema, query.ID())
+		if err != nil {
+			return err
+		}
+		stmt.Where(condition)
+	}
+
+	buildFilter(stmt, query, schema)
+
+	return query.Error()
+}
+
+// primaryKeyCondition ids given as string, e.g. from url paths, are parsed to numeric primary keys
+func primaryKeyCondition(schema *gormSchema.Schema, id interface{}) (clause.Expression, error) {
+	field := schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil, fmt.Errorf("model %s has no primary key", schema.Name)
+	}
+
+	value, err := primaryKeyValue(field, schema.Name, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value}, nil
+}
+
+func primaryKeyValue(field *gormSchema.Field, name string, id interface{}) (interface{}, error) {
+	s, ok := id.(string)
+	if !ok {
+		return id, nil
+	}
+
+	value, err := parseID(field.IndirectFieldType.Kind(), s)
+	if err != nil {
+		return nil, NewEntityNotFoundErr(name, id)
+	}
+
+	return value, nil
+}
+
+// checkEntityID a primary key set on the model must match the id, gorm would add it to the conditions of the update
+// and write no row instead, e.g. PUT /things/1 {"id": 2}
+func checkEntityID(ctx context.Context, schema *gormSchema.Schema, query WriteQueryObject) error {
+	field := schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+
+	entityID, zero := field.ValueOf(ctx, reflect.Indirect(reflect.ValueOf(query.Model())))
+	if zero {
+		return nil
+	}
+
+	id, err := primaryKeyValue(field, schema.Name, query.ID())
+	if err != nil {
+		return err
+	}
+	if fmt.Sprint(entityID) != fmt.Sprint(id) {
+		return NewEntityIDConflictErr(schema.Name, query.ID(), entityID)
+	}
+
+	return nil
+}
+
+func parseID(kind reflect.Kind, id string) (interface{}, error) {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(id, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(id, 10, 64)
+	default:
+		return id, nil
+	}
+}
+
+// schemas parsed outside of statements, the naming strategy does not affect field names
+var schemas sync.Map
+
+// ProtectedFields names of the model fields clients may not write, the primary key and the creation time
+func ProtectedFields(model interface{}) ([]string, error) {
+	schema, err := gormSchema.Parse(model, &schemas, gormSchema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	return writeProtectedFields(schema), nil
+}
+
+// protectedFields are not overwritten by full updates
+func protectedFields(schema *gormSchema.Schema) []string {
+	return append([]string{clause.Associations}, writeProtectedFields(schema)...)
+}
+
+func writeProtectedFields(schema *gormSchema.Schema) []string {
+	fields := make([]string, 0)
+	for _, field := range schema.Fields {
+		if field.PrimaryKey || field.AutoCreateTime > 0 {
+			fields = append(fields, field.Name)
+		}
+	}
+
+	return fields
+}
+
+// reload reads the written entity and its preloads, restricted by the filters of the query as the write was
+func (h *QueryHandler) reload(ctx context.Context, query WriteQueryObject, schema *gormSchema.Schema) (interface{}, error) {
+	condition, err := primaryKeyCondition(schema, query.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	result := h.buildResult(query.Model())
+	stmt := h.db.WithContext(ctx).Model(result).Where(condition)
+	buildFilter(stmt, query, schema)
+	if err = query.Error(); err != nil {
+		return nil, err
+	}
+	buildPreloads(stmt, query.Preloads())
+
+	res := stmt.Limit(1).Find(result)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, NewEntityNotFoundErr(schema.Name, query.ID())
+	}
+
+	return result, nil
+}
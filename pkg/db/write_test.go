@@ -0,0 +1,196 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryHandler_HandleUpdate(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "write.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	for _, title := range []string{"foo", "bar"} {
+		assert.Nil(t, conn.Create(&streamedThing{Title: title}).Error)
+	}
+
+	tests := []struct {
+		entity   *streamedThing
+		id       interface{}
+		err      error
+		expected []string
+	}{
+		{&streamedThing{Title: "baz"}, "1", nil, []string{"baz", "bar"}},
+		{&streamedThing{ID: 2, Title: "qux"}, "2", nil, []string{"baz", "qux"}},
+		{&streamedThing{ID: 2, Title: "quux"}, "1", NewEntityIDConflictErr("streamedThing", "1", 2), []string{"baz", "qux"}},
+		{&streamedThing{Title: "quux"}, "3", NewEntityNotFoundErr("streamedThing", "3"), []string{"baz", "qux"}},
+	}
+
+	h := NewQueryHandler(conn)
+	for _, test := range tests {
+		q := NewUpdateQuery(test.entity, test.id)
+		h.Handle(q)
+		assert.Equal(t, test.err, q.Error(), test.id)
+
+		things := make([]streamedThing, 0)
+		assert.Nil(t, conn.Order("id").Find(&things).Error)
+		titles := make([]string, 0)
+		for _, thing := range things {
+			titles = append(titles, thing.Title)
+		}
+		assert.Equal(t, test.expected, titles, test.id)
+	}
+}
+
+func TestQueryHandler_HandleScopedWrites(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "scoped.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	assert.Nil(t, conn.Create(&[]streamedThing{{Title: "foo", Views: 1}, {Title: "bar", Views: 2}}).Error)
+
+	other := []Filter{{"Views", "=", 1}}
+	own := []Filter{{"Views", "=", 2}}
+
+	h := NewQueryHandler(conn)
+	queries := []*WriteQuery{
+		NewUpdateQuery(&streamedThing{Title: "baz", Views: 2}, "2"),
+		NewPatchQuery(&streamedThing{}, "2", map[string]interface{}{"Title": "baz"}),
+		NewPatchQuery(&streamedThing{}, "2", map[string]interface{}{}),
+		NewDeleteQuery(&streamedThing{}, "2"),
+	}
+	for _, q := range queries {
+		// rows of other scopes are neither written nor read
+		q.SetFilters(other)
+		h.Handle(q)
+		assert.Equal(t, NewEntityNotFoundErr("streamedThing", "2"), q.Error(), q.Operation())
+		assert.Nil(t, q.Result(), q.Operation())
+		assert.Equal(t, []string{"foo", "bar"}, thingTitles(t, conn), q.Operation())
+	}
+
+	q := NewUpdateQuery(&streamedThing{Title: "baz", Views: 2}, "2")
+	q.SetFilters(own)
+	h.Handle(q)
+	assert.Nil(t, q.Error())
+	assert.Equal(t, &streamedThing{ID: 2, Title: "baz", Views: 2}, q.Result())
+}
+
+func TestQueryHandler_HandleCreate(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "create.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	h := NewQueryHandler(conn)
+
+	thing := &streamedThing{Title: "foo", Views: 1}
+	q := NewCreateQuery(thing)
+	h.Handle(q)
+	assert.Nil(t, q.Error())
+	assert.Equal(t, int64(1), q.RowsAffected())
+	assert.Equal(t, &streamedThing{ID: 1, Title: "foo", Views: 1}, q.Result())
+
+	q = NewCreateQuery(&[]streamedThing{{Title: "bar"}, {Title: "baz"}})
+	h.Handle(q)
+	assert.Nil(t, q.Error())
+	assert.Equal(t, int64(2), q.RowsAffected())
+
+	// omitted fields are not written
+	q = NewCreateQuery(&streamedThing{Title: "qux", Views: 3})
+	q.SetOmitted([]string{"Views"})
+	h.Handle(q)
+	assert.Nil(t, q.Error())
+
+	things := make([]streamedThing, 0)
+	assert.Nil(t, conn.Order("id").Find(&things).Error)
+	assert.Equal(t, []streamedThing{{1, "foo", 1}, {2, "bar", 0}, {3, "baz", 0}, {4, "qux", 0}}, things)
+}
+
+func TestQueryHandler_HandlePatch(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "patch.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	assert.Nil(t, conn.Create(&[]streamedThing{{Title: "foo", Views: 1}, {Title: "bar", Views: 2}, {Title: "baz", Views: 2}}).Error)
+
+	tests := []struct {
+		id       interface{}
+		values   map[string]interface{}
+		filters  []Filter
+		err      error
+		rows     int64
+		result   interface{}
+		expected []streamedThing
+	}{
+		// keys are field or column names, fields not given are kept
+		{"1", map[string]interface{}{"Title": "qux"}, nil, nil, 1, &streamedThing{1, "qux", 1}, []streamedThing{{1, "qux", 1}, {2, "bar", 2}, {3, "baz", 2}}},
+		{"1", map[string]interface{}{"views": 5}, nil, nil, 1, &streamedThing{1, "qux", 5}, []streamedThing{{1, "qux", 5}, {2, "bar", 2}, {3, "baz", 2}}},
+		// nothing to write, the entity is read as is
+		{"2", map[string]interface{}{}, nil, nil, 0, &streamedThing{2, "bar", 2}, []streamedThing{{1, "qux", 5}, {2, "bar", 2}, {3, "baz", 2}}},
+		{"4", map[string]interface{}{"Title": "quux"}, nil, NewEntityNotFoundErr("streamedThing", "4"), 0, nil, []streamedThing{{1, "qux", 5}, {2, "bar", 2}, {3, "baz", 2}}},
+		// all rows matching the filters without result
+		{nil, map[string]interface{}{"Views": 3}, []Filter{{"Views", "=", 2}}, nil, 2, nil, []streamedThing{{1, "qux", 5}, {2, "bar", 3}, {3, "baz", 3}}},
+	}
+
+	h := NewQueryHandler(conn)
+	for _, test := range tests {
+		q := NewPatchQuery(&streamedThing{}, test.id, test.values)
+		q.SetFilters(test.filters)
+		h.Handle(q)
+		assert.Equal(t, test.err, q.Error(), test.values)
+		assert.Equal(t, test.rows, q.RowsAffected(), test.values)
+		assert.Equal(t, test.result, q.Result(), test.values)
+
+		things := make([]streamedThing, 0)
+		assert.Nil(t, conn.Order("id").Find(&things).Error)
+		assert.Equal(t, test.expected, things, test.values)
+	}
+}
+
+func TestQueryHandler_HandleDelete(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "delete.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	assert.Nil(t, conn.Create(&[]streamedThing{{Title: "foo", Views: 1}, {Title: "bar", Views: 2}, {Title: "baz", Views: 2}}).Error)
+
+	tests := []struct {
+		id       interface{}
+		filters  []Filter
+		err      error
+		rows     int64
+		expected []string
+	}{
+		{"1", nil, nil, 1, []string{"bar", "baz"}},
+		{"1", nil, NewEntityNotFoundErr("streamedThing", "1"), 0, []string{"bar", "baz"}},
+		{"x", nil, NewEntityNotFoundErr("streamedThing", "x"), 0, []string{"bar", "baz"}},
+		{nil, []Filter{{"Views", "=", 2}}, nil, 2, []string{}},
+	}
+
+	h := NewQueryHandler(conn)
+	for _, test := range tests {
+		q := NewDeleteQuery(&streamedThing{}, test.id)
+		q.SetFilters(test.filters)
+		h.Handle(q)
+		assert.Equal(t, test.err, q.Error(), test.id)
+		assert.Equal(t, test.rows, q.RowsAffected(), test.id)
+		assert.Nil(t, q.Result(), test.id)
+		assert.Equal(t, test.expected, thingTitles(t, conn), test.id)
+	}
+}
+
+func TestQueryHandler_HandleMissingConditions(t *testing.T) {
+	conn, err := NewDatabase(Config{Driver: Sqlite, DatabaseName: filepath.Join(t.TempDir(), "conditions.sqlite")})
+	assert.Nil(t, err)
+	assert.Nil(t, conn.AutoMigrate(&streamedThing{}))
+	assert.Nil(t, conn.Create(&[]streamedThing{{Title: "foo"}, {Title: "bar"}}).Error)
+
+	h := NewQueryHandler(conn)
+	queries := []*WriteQuery{
+		NewUpdateQuery(&streamedThing{Title: "baz"}, nil),
+		NewPatchQuery(&streamedThing{}, nil, map[string]interface{}{"Title": "baz"}),
+		NewDeleteQuery(&streamedThing{}, nil),
+	}
+	for _, q := range queries {
+		// writes without id or filters would affect every row
+		h.Handle(q)
+		assert.Equal(t, NewMissingConditionsErr(q.Operation()), q.Error(), q.Operation())
+		assert.Equal(t, []string{"foo", "bar"}, thingTitles(t, conn), q.Operation())
+	}
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const CommandUsage = "migrate up | down [steps] | status"
+
+// Command runs the migrate sub commands
+// up applies all pending migrations, down reverts the last steps migrations, 1 by default, status lists all migrations
+func Command(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: " + CommandUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %d %s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %s", args[1])
+			}
+			steps = n
+		}
+
+		reverted, err := m.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "reverted %d %s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %s, usage: %s", args[0], CommandUsage)
+	}
+}
@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/fnv"
+
+	"gorm.io/gorm"
+)
+
+// lockStatements session level locks of the dialects, other dialects, e.g. sqlite, run without lock
+var lockStatements = map[string][2]string{
+	"postgres": {"SELECT pg_advisory_lock(?)", "SELECT pg_advisory_unlock(?)"},
+	"mysql":    {"SELECT GET_LOCK(?, -1)", "SELECT RELEASE_LOCK(?)"},
+}
+
+// locked runs fn on a single connection holding the lock of the migrations table, concurrent migrators wait for it
+func (m *Migrator) locked(ctx context.Context, fn func(db *gorm.DB) error) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	db := m.db.Session(&gorm.Session{NewDB: true, Context: ctx})
+	db.Statement.ConnPool = conn
+
+	statements, ok := lockStatements[db.Dialector.Name()]
+	if ok {
+		key := m.lockKey(db.Dialector.Name())
+		if err = lock(db, statements[0], key); err != nil {
+			return err
+		}
+		// the lock has to be released even if ctx is done as the connection is returned to the pool
+		defer db.WithContext(context.Background()).Exec(statements[1], key)
+	}
+
+	if err = m.createTable(db); err != nil {
+		return err
+	}
+
+	return fn(db)
+}
+
+// lockKey advisory locks of postgres are identified by a number, named locks of mysql by the table name
+func (m *Migrator) lockKey(dialect string) interface{} {
+	if dialect != "postgres" {
+		return m.table
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(m.table))
+
+	return int64(h.Sum64())
+}
+
+// lock GET_LOCK returns 1 if the lock was obtained, pg_advisory_lock returns void
+func lock(db *gorm.DB, statement string, key interface{}) error {
+	if db.Dialector.Name() != "mysql" {
+		return db.Exec(statement, key).Error
+	}
+
+	var result sql.NullInt64
+	if err := db.Raw(statement, key).Row().Scan(&result); err != nil {
+		return err
+	}
+	if result.Int64 != 1 {
+		return errors.New("could not obtain migration lock")
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultTable = "schema_migrations"
+
+// Migration Version orders the migrations, e.g. the creation timestamp 20240131120000
+// Up and Down run in a transaction together with the bookkeeping of the migrations table
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// appliedMigration row of the migrations table
+type appliedMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+type DuplicateVersionErr struct {
+	version int64
+}
+
+func (e DuplicateVersionErr) Error() string {
+	return fmt.Sprintf("duplicate migration version %d", e.version)
+}
+
+func NewDuplicateVersionErr(version int64) DuplicateVersionErr {
+	return DuplicateVersionErr{version}
+}
+
+type IrreversibleMigrationErr struct {
+	version int64
+	name    string
+}
+
+func (e IrreversibleMigrationErr) Error() string {
+	return fmt.Sprintf("migration %d %s has no down migration", e.version, e.name)
+}
+
+func NewIrreversibleMigrationErr(m Migration) IrreversibleMigrationErr {
+	return IrreversibleMigrationErr{m.Version, m.Name}
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	table      string
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) *Migrator {
+	return &Migrator{
+		db:    db,
+		table: defaultTable,
+	}
+}
+
+// SetTable name of the table recording applied migrations, schema_migrations by default
+func (m *Migrator) SetTable(table string) {
+	m.table = table
+}
+
+func (m *Migrator) Register(migrations ...Migration) error {
+	for _, migration := range migrations {
+		for _, registered := range m.migrations {
+			if registered.Version == migration.Version {
+				return NewDuplicateVersionErr(migration.Version)
+			}
+		}
+		m.migrations = append(m.migrations, migration)
+	}
+
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+
+	return nil
+}
+
+// Up applies all pending migrations in order of their versions
+func (m *Migrator) Up(ctx context.Context) (applied []Migration, err error) {
+	err = m.locked(ctx, func(db *gorm.DB) error {
+		status, err := m.status(db)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range status {
+			if s.Applied {
+				continue
+			}
+			if err = m.apply(db, s.Migration); err != nil {
+				return err
+			}
+			applied = append(applied, s.Migration)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Down reverts the last steps applied migrations
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []Migration, err error) {
+	err = m.locked(ctx, func(db *gorm.DB) error {
+		status, err := m.status(db)
+		if err != nil {
+			return err
+		}
+
+		for i := len(status) - 1; i >= 0 && len(reverted) < steps; i-- {
+			if !status[i].Applied {
+				continue
+			}
+			if err = m.revert(db, status[i].Migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, status[i].Migration)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Status of all registered migrations in order of their versions
+func (m *Migrator) Status(ctx context.Context) (status []Status, err error) {
+	db := m.db.WithContext(ctx)
+	if err = m.createTable(db); err != nil {
+		return nil, err
+	}
+
+	return m.status(db)
+}
+
+func (m *Migrator) createTable(db *gorm.DB) error {
+	return db.Table(m.table).AutoMigrate(&appliedMigration{})
+}
+
+func (m *Migrator) status(db *gorm.DB) ([]Status, error) {
+	rows := make([]appliedMigration, 0)
+	if err := db.Table(m.table).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+
+	status := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := Status{Migration: migration}
+		if at, ok := applied[migration.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &at
+		}
+		status = append(status, s)
+	}
+
+	return status, nil
+}
+
+func (m *Migrator) apply(db *gorm.DB, migration Migration) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if migration.Up != nil {
+			if err := migration.Up(tx); err != nil {
+				return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+		}
+
+		row := appliedMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+		return tx.Table(m.table).Create(&row).Error
+	})
+}
+
+func (m *Migrator) revert(db *gorm.DB, migration Migration) error {
+	if migration.Down == nil {
+		return NewIrreversibleMigrationErr(migration)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+
+		return tx.Table(m.table).Delete(&appliedMigration{}, migration.Version).Error
+	})
+}
@@ -0,0 +1,105 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection of an in-memory sqlite database is a database of its own
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	return db
+}
+
+func TestMigrator(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	migrations, err := LoadSQL(fstest.MapFS{
+		"sql/2_add_title.up.sql":     {Data: []byte("ALTER TABLE things ADD title TEXT;")},
+		"sql/2_add_title.down.sql":   {Data: []byte("ALTER TABLE things DROP COLUMN title;")},
+		"sql/1_create_things.up.sql": {Data: []byte("CREATE TABLE things (id INTEGER PRIMARY KEY);")},
+		"sql/README.md":              {Data: []byte("ignored")},
+	}, "sql")
+	assert.Nil(t, err)
+	assert.Len(t, migrations, 2)
+
+	m := NewMigrator(db)
+	assert.Nil(t, m.Register(migrations...))
+	assert.Equal(t, NewDuplicateVersionErr(1), m.Register(Migration{Version: 1}))
+
+	applied, err := m.Up(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, applied, 2)
+	assert.Equal(t, "create_things", applied[0].Name)
+	assert.True(t, db.Migrator().HasColumn("things", "title"))
+
+	applied, err = m.Up(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, applied, 0)
+
+	reverted, err := m.Down(ctx, 1)
+	assert.Nil(t, err)
+	assert.Len(t, reverted, 1)
+	assert.False(t, db.Migrator().HasColumn("things", "title"))
+
+	_, err = m.Down(ctx, 1)
+	assert.Equal(t, NewIrreversibleMigrationErr(migrations[0]), err)
+
+	status, err := m.Status(ctx)
+	assert.Nil(t, err)
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[1].Applied)
+}
+
+func TestMigrator_FailingMigration(t *testing.T) {
+	db := newTestDB(t)
+	m := NewMigrator(db)
+	_ = m.Register(Migration{Version: 1, Name: "failing", Up: func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE TABLE things (id INTEGER)").Error; err != nil {
+			return err
+		}
+		return errors.New("failed")
+	}})
+
+	_, err := m.Up(context.Background())
+	assert.NotNil(t, err)
+	assert.False(t, db.Migrator().HasTable("things"))
+
+	status, _ := m.Status(context.Background())
+	assert.False(t, status[0].Applied)
+}
+
+func TestCommand(t *testing.T) {
+	m := NewMigrator(newTestDB(t))
+	_ = m.Register(Migration{Version: 1, Name: "noop", Down: func(tx *gorm.DB) error { return nil }})
+	out := &bytes.Buffer{}
+
+	assert.Nil(t, Command(context.Background(), m, []string{"up"}, out))
+	assert.Equal(t, "applied 1 noop\n", out.String())
+
+	out.Reset()
+	assert.Nil(t, Command(context.Background(), m, []string{"down", "1"}, out))
+	assert.Equal(t, "reverted 1 noop\n", out.String())
+
+	out.Reset()
+	assert.Nil(t, Command(context.Background(), m, []string{"status"}, out))
+	assert.Contains(t, out.String(), "pending")
+
+	assert.NotNil(t, Command(context.Background(), m, []string{"down", "x"}, out))
+	assert.NotNil(t, Command(context.Background(), m, []string{"sideways"}, out))
+}
@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// sqlFilePattern 20240131120000_create_things.up.sql
+var sqlFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// LoadSQL migrations of the files <version>_<name>.up.sql and <version>_<name>.down.sql in dir
+// a file may contain several statements, mysql requires the multiStatements dsn option for that
+func LoadSQL(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	versions := make([]int64, 0)
+	for _, entry := range entries {
+		match := sqlFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version of %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+			versions = append(versions, version)
+		}
+		if match[3] == "up" {
+			m.Up = execSQL(string(content))
+		} else {
+			m.Down = execSQL(string(content))
+		}
+	}
+
+	migrations := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		migrations = append(migrations, *byVersion[version])
+	}
+
+	return migrations, nil
+}
+
+func execSQL(statements string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec(statements).Error
+	}
+}
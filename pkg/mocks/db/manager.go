@@ -9,6 +9,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	db "github.com/mangalores/go-api-skeleton/pkg/db"
+	gorm "gorm.io/gorm"
 )
 
 // MockRepository is a mock of Repository interface.
@@ -61,3 +62,68 @@ func (mr *MockRepositoryMockRecorder) Supports(t interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Supports", reflect.TypeOf((*MockRepository)(nil).Supports), t)
 }
+
+// MockTransactionalRepository is a mock of TransactionalRepository interface.
+type MockTransactionalRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionalRepositoryMockRecorder
+}
+
+// MockTransactionalRepositoryMockRecorder is the mock recorder for MockTransactionalRepository.
+type MockTransactionalRepositoryMockRecorder struct {
+	mock *MockTransactionalRepository
+}
+
+// NewMockTransactionalRepository creates a new mock instance.
+func NewMockTransactionalRepository(ctrl *gomock.Controller) *MockTransactionalRepository {
+	mock := &MockTransactionalRepository{ctrl: ctrl}
+	mock.recorder = &MockTransactionalRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionalRepository) EXPECT() *MockTransactionalRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Handle mocks base method.
+func (m *MockTransactionalRepository) Handle(q db.QueryObject) db.QueryObject {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Handle", q)
+	ret0, _ := ret[0].(db.QueryObject)
+	return ret0
+}
+
+// Handle indicates an expected call of Handle.
+func (mr *MockTransactionalRepositoryMockRecorder) Handle(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockTransactionalRepository)(nil).Handle), q)
+}
+
+// Supports mocks base method.
+func (m *MockTransactionalRepository) Supports(t interface{}) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Supports", t)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Supports indicates an expected call of Supports.
+func (mr *MockTransactionalRepositoryMockRecorder) Supports(t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Supports", reflect.TypeOf((*MockTransactionalRepository)(nil).Supports), t)
+}
+
+// WithDB mocks base method.
+func (m *MockTransactionalRepository) WithDB(tx *gorm.DB) db.Repository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithDB", tx)
+	ret0, _ := ret[0].(db.Repository)
+	return ret0
+}
+
+// WithDB indicates an expected call of WithDB.
+func (mr *MockTransactionalRepositoryMockRecorder) WithDB(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithDB", reflect.TypeOf((*MockTransactionalRepository)(nil).WithDB), tx)
+}
@@ -5,7 +5,9 @@
 package mock_db
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	db "github.com/mangalores/go-api-skeleton/pkg/db"
@@ -34,6 +36,20 @@ func (m *MockQueryObject) EXPECT() *MockQueryObjectMockRecorder {
 	return m.recorder
 }
 
+// Context mocks base method.
+func (m *MockQueryObject) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockQueryObjectMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockQueryObject)(nil).Context))
+}
+
 // Error mocks base method.
 func (m *MockQueryObject) Error() error {
 	m.ctrl.T.Helper()
@@ -48,6 +64,20 @@ func (mr *MockQueryObjectMockRecorder) Error() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockQueryObject)(nil).Error))
 }
 
+// Fields mocks base method.
+func (m *MockQueryObject) Fields() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fields")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Fields indicates an expected call of Fields.
+func (mr *MockQueryObjectMockRecorder) Fields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fields", reflect.TypeOf((*MockQueryObject)(nil).Fields))
+}
+
 // Model mocks base method.
 func (m *MockQueryObject) Model() interface{} {
 	m.ctrl.T.Helper()
@@ -90,6 +120,18 @@ func (mr *MockQueryObjectMockRecorder) Result() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockQueryObject)(nil).Result))
 }
 
+// SetContext mocks base method.
+func (m *MockQueryObject) SetContext(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetContext", ctx)
+}
+
+// SetContext indicates an expected call of SetContext.
+func (mr *MockQueryObjectMockRecorder) SetContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContext", reflect.TypeOf((*MockQueryObject)(nil).SetContext), ctx)
+}
+
 // SetError mocks base method.
 func (m *MockQueryObject) SetError(err error) {
 	m.ctrl.T.Helper()
@@ -114,6 +156,20 @@ func (mr *MockQueryObjectMockRecorder) SetResult(result interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResult", reflect.TypeOf((*MockQueryObject)(nil).SetResult), result)
 }
 
+// Timeout mocks base method.
+func (m *MockQueryObject) Timeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Timeout indicates an expected call of Timeout.
+func (mr *MockQueryObjectMockRecorder) Timeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeout", reflect.TypeOf((*MockQueryObject)(nil).Timeout))
+}
+
 // MockFilteredQueryObject is a mock of FilteredQueryObject interface.
 type MockFilteredQueryObject struct {
 	ctrl     *gomock.Controller
@@ -137,6 +193,20 @@ func (m *MockFilteredQueryObject) EXPECT() *MockFilteredQueryObjectMockRecorder
 	return m.recorder
 }
 
+// Context mocks base method.
+func (m *MockFilteredQueryObject) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockFilteredQueryObjectMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockFilteredQueryObject)(nil).Context))
+}
+
 // Error mocks base method.
 func (m *MockFilteredQueryObject) Error() error {
 	m.ctrl.T.Helper()
@@ -151,6 +221,34 @@ func (mr *MockFilteredQueryObjectMockRecorder) Error() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockFilteredQueryObject)(nil).Error))
 }
 
+// Fields mocks base method.
+func (m *MockFilteredQueryObject) Fields() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fields")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Fields indicates an expected call of Fields.
+func (mr *MockFilteredQueryObjectMockRecorder) Fields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fields", reflect.TypeOf((*MockFilteredQueryObject)(nil).Fields))
+}
+
+// FilterGroups mocks base method.
+func (m *MockFilteredQueryObject) FilterGroups() []db.FilterGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterGroups")
+	ret0, _ := ret[0].([]db.FilterGroup)
+	return ret0
+}
+
+// FilterGroups indicates an expected call of FilterGroups.
+func (mr *MockFilteredQueryObjectMockRecorder) FilterGroups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterGroups", reflect.TypeOf((*MockFilteredQueryObject)(nil).FilterGroups))
+}
+
 // Filters mocks base method.
 func (m *MockFilteredQueryObject) Filters() []db.Filter {
 	m.ctrl.T.Helper()
@@ -207,6 +305,18 @@ func (mr *MockFilteredQueryObjectMockRecorder) Result() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockFilteredQueryObject)(nil).Result))
 }
 
+// SetContext mocks base method.
+func (m *MockFilteredQueryObject) SetContext(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetContext", ctx)
+}
+
+// SetContext indicates an expected call of SetContext.
+func (mr *MockFilteredQueryObjectMockRecorder) SetContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContext", reflect.TypeOf((*MockFilteredQueryObject)(nil).SetContext), ctx)
+}
+
 // SetError mocks base method.
 func (m *MockFilteredQueryObject) SetError(err error) {
 	m.ctrl.T.Helper()
@@ -231,6 +341,20 @@ func (mr *MockFilteredQueryObjectMockRecorder) SetResult(result interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResult", reflect.TypeOf((*MockFilteredQueryObject)(nil).SetResult), result)
 }
 
+// Timeout mocks base method.
+func (m *MockFilteredQueryObject) Timeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Timeout indicates an expected call of Timeout.
+func (mr *MockFilteredQueryObjectMockRecorder) Timeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeout", reflect.TypeOf((*MockFilteredQueryObject)(nil).Timeout))
+}
+
 // MockSlicedQueryObject is a mock of SlicedQueryObject interface.
 type MockSlicedQueryObject struct {
 	ctrl     *gomock.Controller
@@ -254,6 +378,20 @@ func (m *MockSlicedQueryObject) EXPECT() *MockSlicedQueryObjectMockRecorder {
 	return m.recorder
 }
 
+// Context mocks base method.
+func (m *MockSlicedQueryObject) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockSlicedQueryObjectMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockSlicedQueryObject)(nil).Context))
+}
+
 // Error mocks base method.
 func (m *MockSlicedQueryObject) Error() error {
 	m.ctrl.T.Helper()
@@ -268,6 +406,34 @@ func (mr *MockSlicedQueryObjectMockRecorder) Error() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockSlicedQueryObject)(nil).Error))
 }
 
+// Fields mocks base method.
+func (m *MockSlicedQueryObject) Fields() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fields")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Fields indicates an expected call of Fields.
+func (mr *MockSlicedQueryObjectMockRecorder) Fields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fields", reflect.TypeOf((*MockSlicedQueryObject)(nil).Fields))
+}
+
+// FilterGroups mocks base method.
+func (m *MockSlicedQueryObject) FilterGroups() []db.FilterGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterGroups")
+	ret0, _ := ret[0].([]db.FilterGroup)
+	return ret0
+}
+
+// FilterGroups indicates an expected call of FilterGroups.
+func (mr *MockSlicedQueryObjectMockRecorder) FilterGroups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterGroups", reflect.TypeOf((*MockSlicedQueryObject)(nil).FilterGroups))
+}
+
 // Filters mocks base method.
 func (m *MockSlicedQueryObject) Filters() []db.Filter {
 	m.ctrl.T.Helper()
@@ -324,6 +490,18 @@ func (mr *MockSlicedQueryObjectMockRecorder) Result() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockSlicedQueryObject)(nil).Result))
 }
 
+// SetContext mocks base method.
+func (m *MockSlicedQueryObject) SetContext(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetContext", ctx)
+}
+
+// SetContext indicates an expected call of SetContext.
+func (mr *MockSlicedQueryObjectMockRecorder) SetContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContext", reflect.TypeOf((*MockSlicedQueryObject)(nil).SetContext), ctx)
+}
+
 // SetError mocks base method.
 func (m *MockSlicedQueryObject) SetError(err error) {
 	m.ctrl.T.Helper()
@@ -361,3 +539,785 @@ func (mr *MockSlicedQueryObjectMockRecorder) Slice() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Slice", reflect.TypeOf((*MockSlicedQueryObject)(nil).Slice))
 }
+
+// Timeout mocks base method.
+func (m *MockSlicedQueryObject) Timeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Timeout indicates an expected call of Timeout.
+func (mr *MockSlicedQueryObjectMockRecorder) Timeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeout", reflect.TypeOf((*MockSlicedQueryObject)(nil).Timeout))
+}
+
+// MockIterator is a mock of Iterator interface.
+type MockIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockIteratorMockRecorder
+}
+
+// MockIteratorMockRecorder is the mock recorder for MockIterator.
+type MockIteratorMockRecorder struct {
+	mock *MockIterator
+}
+
+// NewMockIterator creates a new mock instance.
+func NewMockIterator(ctrl *gomock.Controller) *MockIterator {
+	mock := &MockIterator{ctrl: ctrl}
+	mock.recorder = &MockIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIterator) EXPECT() *MockIteratorMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockIterator) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIteratorMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIterator)(nil).Close))
+}
+
+// Err mocks base method.
+func (m *MockIterator) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockIteratorMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockIterator)(nil).Err))
+}
+
+// Next mocks base method.
+func (m *MockIterator) Next() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockIteratorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockIterator)(nil).Next))
+}
+
+// Value mocks base method.
+func (m *MockIterator) Value() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Value")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// Value indicates an expected call of Value.
+func (mr *MockIteratorMockRecorder) Value() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Value", reflect.TypeOf((*MockIterator)(nil).Value))
+}
+
+// MockStreamedQueryObject is a mock of StreamedQueryObject interface.
+type MockStreamedQueryObject struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamedQueryObjectMockRecorder
+}
+
+// MockStreamedQueryObjectMockRecorder is the mock recorder for MockStreamedQueryObject.
+type MockStreamedQueryObjectMockRecorder struct {
+	mock *MockStreamedQueryObject
+}
+
+// NewMockStreamedQueryObject creates a new mock instance.
+func NewMockStreamedQueryObject(ctrl *gomock.Controller) *MockStreamedQueryObject {
+	mock := &MockStreamedQueryObject{ctrl: ctrl}
+	mock.recorder = &MockStreamedQueryObjectMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamedQueryObject) EXPECT() *MockStreamedQueryObjectMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockStreamedQueryObject) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamedQueryObjectMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamedQueryObject)(nil).Context))
+}
+
+// Error mocks base method.
+func (m *MockStreamedQueryObject) Error() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Error")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockStreamedQueryObjectMockRecorder) Error() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockStreamedQueryObject)(nil).Error))
+}
+
+// Fields mocks base method.
+func (m *MockStreamedQueryObject) Fields() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fields")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Fields indicates an expected call of Fields.
+func (mr *MockStreamedQueryObjectMockRecorder) Fields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fields", reflect.TypeOf((*MockStreamedQueryObject)(nil).Fields))
+}
+
+// FilterGroups mocks base method.
+func (m *MockStreamedQueryObject) FilterGroups() []db.FilterGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterGroups")
+	ret0, _ := ret[0].([]db.FilterGroup)
+	return ret0
+}
+
+// FilterGroups indicates an expected call of FilterGroups.
+func (mr *MockStreamedQueryObjectMockRecorder) FilterGroups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterGroups", reflect.TypeOf((*MockStreamedQueryObject)(nil).FilterGroups))
+}
+
+// Filters mocks base method.
+func (m *MockStreamedQueryObject) Filters() []db.Filter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Filters")
+	ret0, _ := ret[0].([]db.Filter)
+	return ret0
+}
+
+// Filters indicates an expected call of Filters.
+func (mr *MockStreamedQueryObjectMockRecorder) Filters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filters", reflect.TypeOf((*MockStreamedQueryObject)(nil).Filters))
+}
+
+// Iterator mocks base method.
+func (m *MockStreamedQueryObject) Iterator() db.Iterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterator")
+	ret0, _ := ret[0].(db.Iterator)
+	return ret0
+}
+
+// Iterator indicates an expected call of Iterator.
+func (mr *MockStreamedQueryObjectMockRecorder) Iterator() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterator", reflect.TypeOf((*MockStreamedQueryObject)(nil).Iterator))
+}
+
+// Model mocks base method.
+func (m *MockStreamedQueryObject) Model() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Model")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// Model indicates an expected call of Model.
+func (mr *MockStreamedQueryObjectMockRecorder) Model() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Model", reflect.TypeOf((*MockStreamedQueryObject)(nil).Model))
+}
+
+// Preloads mocks base method.
+func (m *MockStreamedQueryObject) Preloads() []db.Preload {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preloads")
+	ret0, _ := ret[0].([]db.Preload)
+	return ret0
+}
+
+// Preloads indicates an expected call of Preloads.
+func (mr *MockStreamedQueryObjectMockRecorder) Preloads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preloads", reflect.TypeOf((*MockStreamedQueryObject)(nil).Preloads))
+}
+
+// Result mocks base method.
+func (m *MockStreamedQueryObject) Result() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Result")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// Result indicates an expected call of Result.
+func (mr *MockStreamedQueryObjectMockRecorder) Result() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockStreamedQueryObject)(nil).Result))
+}
+
+// SetContext mocks base method.
+func (m *MockStreamedQueryObject) SetContext(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetContext", ctx)
+}
+
+// SetContext indicates an expected call of SetContext.
+func (mr *MockStreamedQueryObjectMockRecorder) SetContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContext", reflect.TypeOf((*MockStreamedQueryObject)(nil).SetContext), ctx)
+}
+
+// SetError mocks base method.
+func (m *MockStreamedQueryObject) SetError(err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetError", err)
+}
+
+// SetError indicates an expected call of SetError.
+func (mr *MockStreamedQueryObjectMockRecorder) SetError(err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetError", reflect.TypeOf((*MockStreamedQueryObject)(nil).SetError), err)
+}
+
+// SetIterator mocks base method.
+func (m *MockStreamedQueryObject) SetIterator(it db.Iterator) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetIterator", it)
+}
+
+// SetIterator indicates an expected call of SetIterator.
+func (mr *MockStreamedQueryObjectMockRecorder) SetIterator(it interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIterator", reflect.TypeOf((*MockStreamedQueryObject)(nil).SetIterator), it)
+}
+
+// SetResult mocks base method.
+func (m *MockStreamedQueryObject) SetResult(result interface{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetResult", result)
+}
+
+// SetResult indicates an expected call of SetResult.
+func (mr *MockStreamedQueryObjectMockRecorder) SetResult(result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResult", reflect.TypeOf((*MockStreamedQueryObject)(nil).SetResult), result)
+}
+
+// SetStream mocks base method.
+func (m *MockStreamedQueryObject) SetStream(flag bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetStream", flag)
+}
+
+// SetStream indicates an expected call of SetStream.
+func (mr *MockStreamedQueryObjectMockRecorder) SetStream(flag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStream", reflect.TypeOf((*MockStreamedQueryObject)(nil).SetStream), flag)
+}
+
+// Slice mocks base method.
+func (m *MockStreamedQueryObject) Slice() *db.Slice {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Slice")
+	ret0, _ := ret[0].(*db.Slice)
+	return ret0
+}
+
+// Slice indicates an expected call of Slice.
+func (mr *MockStreamedQueryObjectMockRecorder) Slice() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Slice", reflect.TypeOf((*MockStreamedQueryObject)(nil).Slice))
+}
+
+// Stream mocks base method.
+func (m *MockStreamedQueryObject) Stream() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockStreamedQueryObjectMockRecorder) Stream() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockStreamedQueryObject)(nil).Stream))
+}
+
+// Timeout mocks base method.
+func (m *MockStreamedQueryObject) Timeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Timeout indicates an expected call of Timeout.
+func (mr *MockStreamedQueryObjectMockRecorder) Timeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeout", reflect.TypeOf((*MockStreamedQueryObject)(nil).Timeout))
+}
+
+// MockIdentifiedQueryObject is a mock of IdentifiedQueryObject interface.
+type MockIdentifiedQueryObject struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentifiedQueryObjectMockRecorder
+}
+
+// MockIdentifiedQueryObjectMockRecorder is the mock recorder for MockIdentifiedQueryObject.
+type MockIdentifiedQueryObjectMockRecorder struct {
+	mock *MockIdentifiedQueryObject
+}
+
+// NewMockIdentifiedQueryObject creates a new mock instance.
+func NewMockIdentifiedQueryObject(ctrl *gomock.Controller) *MockIdentifiedQueryObject {
+	mock := &MockIdentifiedQueryObject{ctrl: ctrl}
+	mock.recorder = &MockIdentifiedQueryObjectMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentifiedQueryObject) EXPECT() *MockIdentifiedQueryObjectMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockIdentifiedQueryObject) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockIdentifiedQueryObjectMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).Context))
+}
+
+// Error mocks base method.
+func (m *MockIdentifiedQueryObject) Error() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Error")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockIdentifiedQueryObjectMockRecorder) Error() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).Error))
+}
+
+// Fields mocks base method.
+func (m *MockIdentifiedQueryObject) Fields() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fields")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Fields indicates an expected call of Fields.
+func (mr *MockIdentifiedQueryObjectMockRecorder) Fields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fields", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).Fields))
+}
+
+// ID mocks base method.
+func (m *MockIdentifiedQueryObject) ID() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ID")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// ID indicates an expected call of ID.
+func (mr *MockIdentifiedQueryObjectMockRecorder) ID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).ID))
+}
+
+// Model mocks base method.
+func (m *MockIdentifiedQueryObject) Model() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Model")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// Model indicates an expected call of Model.
+func (mr *MockIdentifiedQueryObjectMockRecorder) Model() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Model", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).Model))
+}
+
+// Preloads mocks base method.
+func (m *MockIdentifiedQueryObject) Preloads() []db.Preload {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preloads")
+	ret0, _ := ret[0].([]db.Preload)
+	return ret0
+}
+
+// Preloads indicates an expected call of Preloads.
+func (mr *MockIdentifiedQueryObjectMockRecorder) Preloads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preloads", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).Preloads))
+}
+
+// Result mocks base method.
+func (m *MockIdentifiedQueryObject) Result() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Result")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// Result indicates an expected call of Result.
+func (mr *MockIdentifiedQueryObjectMockRecorder) Result() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).Result))
+}
+
+// SetContext mocks base method.
+func (m *MockIdentifiedQueryObject) SetContext(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetContext", ctx)
+}
+
+// SetContext indicates an expected call of SetContext.
+func (mr *MockIdentifiedQueryObjectMockRecorder) SetContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContext", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).SetContext), ctx)
+}
+
+// SetError mocks base method.
+func (m *MockIdentifiedQueryObject) SetError(err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetError", err)
+}
+
+// SetError indicates an expected call of SetError.
+func (mr *MockIdentifiedQueryObjectMockRecorder) SetError(err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetError", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).SetError), err)
+}
+
+// SetResult mocks base method.
+func (m *MockIdentifiedQueryObject) SetResult(result interface{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetResult", result)
+}
+
+// SetResult indicates an expected call of SetResult.
+func (mr *MockIdentifiedQueryObjectMockRecorder) SetResult(result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResult", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).SetResult), result)
+}
+
+// Timeout mocks base method.
+func (m *MockIdentifiedQueryObject) Timeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Timeout indicates an expected call of Timeout.
+func (mr *MockIdentifiedQueryObjectMockRecorder) Timeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeout", reflect.TypeOf((*MockIdentifiedQueryObject)(nil).Timeout))
+}
+
+// MockWriteQueryObject is a mock of WriteQueryObject interface.
+type MockWriteQueryObject struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriteQueryObjectMockRecorder
+}
+
+// MockWriteQueryObjectMockRecorder is the mock recorder for MockWriteQueryObject.
+type MockWriteQueryObjectMockRecorder struct {
+	mock *MockWriteQueryObject
+}
+
+// NewMockWriteQueryObject creates a new mock instance.
+func NewMockWriteQueryObject(ctrl *gomock.Controller) *MockWriteQueryObject {
+	mock := &MockWriteQueryObject{ctrl: ctrl}
+	mock.recorder = &MockWriteQueryObjectMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriteQueryObject) EXPECT() *MockWriteQueryObjectMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockWriteQueryObject) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockWriteQueryObjectMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockWriteQueryObject)(nil).Context))
+}
+
+// Error mocks base method.
+func (m *MockWriteQueryObject) Error() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Error")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockWriteQueryObjectMockRecorder) Error() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockWriteQueryObject)(nil).Error))
+}
+
+// Fields mocks base method.
+func (m *MockWriteQueryObject) Fields() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fields")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Fields indicates an expected call of Fields.
+func (mr *MockWriteQueryObjectMockRecorder) Fields() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fields", reflect.TypeOf((*MockWriteQueryObject)(nil).Fields))
+}
+
+// FilterGroups mocks base method.
+func (m *MockWriteQueryObject) FilterGroups() []db.FilterGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterGroups")
+	ret0, _ := ret[0].([]db.FilterGroup)
+	return ret0
+}
+
+// FilterGroups indicates an expected call of FilterGroups.
+func (mr *MockWriteQueryObjectMockRecorder) FilterGroups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterGroups", reflect.TypeOf((*MockWriteQueryObject)(nil).FilterGroups))
+}
+
+// Filters mocks base method.
+func (m *MockWriteQueryObject) Filters() []db.Filter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Filters")
+	ret0, _ := ret[0].([]db.Filter)
+	return ret0
+}
+
+// Filters indicates an expected call of Filters.
+func (mr *MockWriteQueryObjectMockRecorder) Filters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filters", reflect.TypeOf((*MockWriteQueryObject)(nil).Filters))
+}
+
+// ID mocks base method.
+func (m *MockWriteQueryObject) ID() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ID")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// ID indicates an expected call of ID.
+func (mr *MockWriteQueryObjectMockRecorder) ID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MockWriteQueryObject)(nil).ID))
+}
+
+// Model mocks base method.
+func (m *MockWriteQueryObject) Model() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Model")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// Model indicates an expected call of Model.
+func (mr *MockWriteQueryObjectMockRecorder) Model() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Model", reflect.TypeOf((*MockWriteQueryObject)(nil).Model))
+}
+
+// Omitted mocks base method.
+func (m *MockWriteQueryObject) Omitted() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Omitted")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Omitted indicates an expected call of Omitted.
+func (mr *MockWriteQueryObjectMockRecorder) Omitted() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Omitted", reflect.TypeOf((*MockWriteQueryObject)(nil).Omitted))
+}
+
+// Operation mocks base method.
+func (m *MockWriteQueryObject) Operation() db.Operation {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Operation")
+	ret0, _ := ret[0].(db.Operation)
+	return ret0
+}
+
+// Operation indicates an expected call of Operation.
+func (mr *MockWriteQueryObjectMockRecorder) Operation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Operation", reflect.TypeOf((*MockWriteQueryObject)(nil).Operation))
+}
+
+// Preloads mocks base method.
+func (m *MockWriteQueryObject) Preloads() []db.Preload {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preloads")
+	ret0, _ := ret[0].([]db.Preload)
+	return ret0
+}
+
+// Preloads indicates an expected call of Preloads.
+func (mr *MockWriteQueryObjectMockRecorder) Preloads() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preloads", reflect.TypeOf((*MockWriteQueryObject)(nil).Preloads))
+}
+
+// Result mocks base method.
+func (m *MockWriteQueryObject) Result() interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Result")
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// Result indicates an expected call of Result.
+func (mr *MockWriteQueryObjectMockRecorder) Result() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockWriteQueryObject)(nil).Result))
+}
+
+// RowsAffected mocks base method.
+func (m *MockWriteQueryObject) RowsAffected() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RowsAffected")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// RowsAffected indicates an expected call of RowsAffected.
+func (mr *MockWriteQueryObjectMockRecorder) RowsAffected() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RowsAffected", reflect.TypeOf((*MockWriteQueryObject)(nil).RowsAffected))
+}
+
+// SetContext mocks base method.
+func (m *MockWriteQueryObject) SetContext(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetContext", ctx)
+}
+
+// SetContext indicates an expected call of SetContext.
+func (mr *MockWriteQueryObjectMockRecorder) SetContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContext", reflect.TypeOf((*MockWriteQueryObject)(nil).SetContext), ctx)
+}
+
+// SetError mocks base method.
+func (m *MockWriteQueryObject) SetError(err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetError", err)
+}
+
+// SetError indicates an expected call of SetError.
+func (mr *MockWriteQueryObjectMockRecorder) SetError(err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetError", reflect.TypeOf((*MockWriteQueryObject)(nil).SetError), err)
+}
+
+// SetResult mocks base method.
+func (m *MockWriteQueryObject) SetResult(result interface{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetResult", result)
+}
+
+// SetResult indicates an expected call of SetResult.
+func (mr *MockWriteQueryObjectMockRecorder) SetResult(result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResult", reflect.TypeOf((*MockWriteQueryObject)(nil).SetResult), result)
+}
+
+// SetRowsAffected mocks base method.
+func (m *MockWriteQueryObject) SetRowsAffected(rows int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetRowsAffected", rows)
+}
+
+// SetRowsAffected indicates an expected call of SetRowsAffected.
+func (mr *MockWriteQueryObjectMockRecorder) SetRowsAffected(rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRowsAffected", reflect.TypeOf((*MockWriteQueryObject)(nil).SetRowsAffected), rows)
+}
+
+// Timeout mocks base method.
+func (m *MockWriteQueryObject) Timeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// Timeout indicates an expected call of Timeout.
+func (mr *MockWriteQueryObjectMockRecorder) Timeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeout", reflect.TypeOf((*MockWriteQueryObject)(nil).Timeout))
+}
+
+// Values mocks base method.
+func (m *MockWriteQueryObject) Values() map[string]interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Values")
+	ret0, _ := ret[0].(map[string]interface{})
+	return ret0
+}
+
+// Values indicates an expected call of Values.
+func (mr *MockWriteQueryObjectMockRecorder) Values() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Values", reflect.TypeOf((*MockWriteQueryObject)(nil).Values))
+}
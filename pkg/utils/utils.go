@@ -2,6 +2,7 @@ package utils
 
 import (
 	"reflect"
+	"strings"
 )
 
 func Contains(elems []string, elem string) bool {
@@ -23,3 +24,13 @@ func StripPointer(i interface{}) interface{} {
 
 	return v.Interface()
 }
+
+// JSONName name of the field in the json representation, ok is false for fields without json tag or ignored ones
+func JSONName(field reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return "", false
+	}
+
+	return name, true
+}
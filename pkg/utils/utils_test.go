@@ -2,6 +2,7 @@ package utils
 
 import (
 	"github.com/stretchr/testify/assert"
+	"reflect"
 	"testing"
 )
 
@@ -37,3 +38,22 @@ func TestStripPointer_OK(t *testing.T) {
 	actual = StripPointer(pointer2)
 	assert.IsType(t, TestStruct{}, actual)
 }
+
+func TestJSONName_OK(t *testing.T) {
+	type tagged struct {
+		Foo string `json:"foo,omitempty"`
+		Bar string `json:"-"`
+		Baz string
+	}
+	typeOf := reflect.TypeOf(tagged{})
+
+	name, ok := JSONName(typeOf.Field(0))
+	assert.True(t, ok)
+	assert.Equal(t, "foo", name)
+
+	_, ok = JSONName(typeOf.Field(1))
+	assert.False(t, ok)
+
+	_, ok = JSONName(typeOf.Field(2))
+	assert.False(t, ok)
+}